@@ -0,0 +1,156 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * The console all questions read from and write to. It is the
+ * standard input and output unless redirected, for example to feed
+ * scripted answers in tests.
+ *-----------------------------------------------------------------*/
+package ask
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+// held while the console is captured (see CaptureConsole)
+var captureMu sync.Mutex
+
+var console = struct {
+	mu  sync.Mutex
+	in  *lineReader
+	out io.Writer
+}{
+	in:  newLineReader(os.Stdin),
+	out: os.Stdout,
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// a line of input or the error that ended the input
+type line struct {
+	text string
+	err  error
+}
+
+// reads the console input one line at a time on behalf of the
+// questions. A single read is in progress at any time; when the
+// question that started it gives up (see InputRequest.WithContext)
+// the line is kept for the next question so that a late answer is not
+// lost.
+type lineReader struct {
+	mu      sync.Mutex
+	in      *bufio.Reader
+	lines   chan line
+	reading bool // a line is being read
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) a line reader of the input
+func newLineReader(in io.Reader) *lineReader {
+	return &lineReader{
+		in:      bufio.NewReader(in),
+		lines:   make(chan line, 1),
+		reading: false,
+	}
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// read a line (with its end-of-line, if any) or give up when the
+// context is done, returning its cause.
+func (r *lineReader) readLine(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	if !r.reading {
+		r.reading = true
+		go func() {
+			text, err := r.in.ReadString('\n')
+			r.lines <- line{text, err}
+		}()
+	}
+	r.mu.Unlock()
+
+	select {
+	case got := <-r.lines:
+		r.mu.Lock()
+		r.reading = false
+		r.mu.Unlock()
+		return got.text, got.err
+	case <-ctx.Done():
+		return "", context.Cause(ctx)
+	}
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// redirect the input and output of all questions. The input is shared
+// by all questions so that input typed (or piped) ahead is not lost.
+func SetConsole(in io.Reader, out io.Writer) {
+	console.mu.Lock()
+	defer console.mu.Unlock()
+
+	console.in = newLineReader(in)
+	console.out = out
+}
+
+// take the console for exclusive use, e.g. by a test: the input and
+// output of all questions are redirected (see SetConsole) until the
+// returned function is called, which puts back the previous console.
+// Meanwhile other captures wait. The function may be called more than
+// once, for example deferred and on cleanup.
+func CaptureConsole(in io.Reader, out io.Writer) (restore func()) {
+	captureMu.Lock()
+	console.mu.Lock()
+	previousIn, previousOut := console.in, console.out
+	console.in = newLineReader(in)
+	console.out = out
+	console.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			console.mu.Lock()
+			console.in, console.out = previousIn, previousOut
+			console.mu.Unlock()
+			captureMu.Unlock()
+		})
+	}
+}
+
+// restore the standard input and output as the console
+func ResetConsole() {
+	SetConsole(os.Stdin, os.Stdout)
+}
+
+// get the writer all questions render to (see SetConsole)
+func Output() io.Writer {
+	console.mu.Lock()
+	defer console.mu.Unlock()
+
+	return console.out
+}
+
+// get the reader all questions read the answers from
+func input() *lineReader {
+	console.mu.Lock()
+	defer console.mu.Unlock()
+
+	return console.in
+}
@@ -0,0 +1,123 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Contextual help for questions. When the user types ? alone on the
+ * line or presses F1 the help text is rendered below the prompt. After
+ * F1 the user can keep on typing where he/she left off; a ? drops any
+ * partial input because it must be alone on the line.
+ *-----------------------------------------------------------------*/
+package ask
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lordofscripts/goask"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// the key the user types (followed by ENTER) to request help
+	HELP_KEY string = "?"
+)
+
+// escape sequences sent by the most common terminals when F1 is pressed
+var f1Sequences []string = []string{"\x1bOP", "\x1b[11~", "\x1b[[A"}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// checks whether the line typed by the user is a request for help:
+// either ? alone on the line or F1 anywhere on it. It returns the text
+// typed before the help request (the partial input) and true if help
+// was requested. An answer that merely ends in ? is not a request.
+func splitHelpRequest(line string) (string, bool) {
+	for _, seq := range f1Sequences {
+		if idx := strings.Index(line, seq); idx > -1 {
+			return line[:idx] + line[idx+len(seq):], true
+		}
+	}
+
+	if strings.TrimSpace(line) == HELP_KEY {
+		return "", true
+	}
+
+	return line, false
+}
+
+// print the prompt and read a line (without the end-of-line) from the
+// console. If showHelp is not nil and the user requests help, the help
+// is rendered and the prompt shown again followed by the partial input
+// (only F1 can follow one, see splitHelpRequest) so that the user can
+// continue typing the rest of the answer. At the end of the input, or
+// when the context is done, the error is returned.
+func readAnswer(ctx context.Context, prompt string, showHelp func()) (string, error) {
+	var partial string = ""
+	for {
+		fmt.Fprint(Output(), prompt, partial)
+		str, err := input().readLine(ctx)
+		str = strings.TrimRight(str, "\r\n")
+		if err != nil {
+			if ctx.Err() != nil {
+				// given up: whatever follows starts on a new line
+				fmt.Fprintln(Output())
+			}
+			return partial + str, err
+		}
+
+		if showHelp != nil {
+			if rest, wantsHelp := splitHelpRequest(str); wantsHelp {
+				showHelp()
+				partial += rest
+				continue
+			}
+		}
+
+		return partial + str, nil
+	}
+}
+
+// render a help text indented below the prompt
+func renderHelp(help string) {
+	fmt.Fprint(Output(), goask.ANSI_PURPLE)
+	for _, line := range strings.Split(help, "\n") {
+		fmt.Fprintf(Output(), "   %c %s\n", goask.ICON_INFORMATION, line)
+	}
+	fmt.Fprint(Output(), goask.ANSI_RESET)
+}
+
+// render the help text of a multiple choice question along with the
+// descriptions of those options that have one.
+func renderChoicesHelp(help string, options []InputSelection) {
+	if len(help) != 0 {
+		renderHelp(help)
+	}
+
+	fmt.Fprint(Output(), goask.ANSI_PURPLE)
+	for _, opt := range options {
+		if len(opt.Help) != 0 {
+			fmt.Fprintf(Output(), "\t%d. %s: %s\n", opt.Number, opt.Text, opt.Help)
+		}
+	}
+	fmt.Fprint(Output(), goask.ANSI_RESET)
+}
+
+// whether any of the options or the question itself has help
+func hasChoicesHelp(help string, options []InputSelection) bool {
+	if len(help) != 0 {
+		return true
+	}
+	for _, opt := range options {
+		if len(opt.Help) != 0 {
+			return true
+		}
+	}
+	return false
+}
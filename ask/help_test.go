@@ -0,0 +1,75 @@
+package ask_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/asktest"
+)
+
+func TestHelpKeyAloneOnTheLine(t *testing.T) {
+	c := asktest.NewConsole(t)
+	question := ask.NewIntInputRequest("Number", 0).WithHelp("Any whole number")
+	c.Ask(question)
+	c.ExpectPrompt("Number [0]: ")
+	c.Send(" ? \n")
+	c.ExpectOutput("Any whole number")
+	c.ExpectPrompt("Number [0]: ")
+	c.Send("5\n")
+	c.Wait()
+	if question.Value != 5 {
+		t.Errorf("answer: want 5 got %d", question.Value)
+	}
+}
+
+func TestHelpKeyAfterAnswerIsNotHelp(t *testing.T) {
+	c := asktest.NewConsole(t)
+	question := ask.NewIntInputRequest("Number", 0).WithHelp("Any whole number")
+	c.Ask(question)
+	c.ExpectPrompt("Number [0]: ")
+	c.Send("4?\n")
+	c.ExpectOutput("Error reading input")
+	c.ExpectPrompt("Number [0]: ")
+	c.Send("4\n")
+	c.Wait()
+	if question.Value != 4 {
+		t.Errorf("answer: want 4 got %d", question.Value)
+	}
+}
+
+func TestHelpF1KeepsPartialInput(t *testing.T) {
+	for _, f1 := range []string{"\x1bOP", "\x1b[11~", "\x1b[[A"} {
+		t.Run(fmt.Sprintf("%q", f1), func(t *testing.T) {
+			c := asktest.NewConsole(t)
+			question := ask.NewIntInputRequest("Number", 0).WithHelp("Any whole number")
+			c.Ask(question)
+			c.ExpectPrompt("Number [0]: ")
+			c.Send("4" + f1 + "\n")
+			c.ExpectOutput("Any whole number")
+			// the prompt is shown again followed by what was typed so far
+			c.ExpectPrompt("Number [0]: 4")
+			c.Send("2\n")
+			c.Wait()
+			if question.Value != 42 {
+				t.Errorf("answer: want 42 got %d", question.Value)
+			}
+		})
+	}
+}
+
+func TestHelpOfChoices(t *testing.T) {
+	c := asktest.NewConsole(t)
+	question := ask.NewMultipleChoiceQuestion("Mode", []ask.InputSelection{
+		ask.NewInputSelection(0, "Mono"),
+		ask.NewInputSelection(1, "Colored").WithHelp("Uses more ink"),
+	}).WithHelp("Select the printing mode")
+	c.Ask(question)
+	c.ExpectPrompt("Enter your choice")
+	c.Send("\x1bOP\n")
+	c.ExpectOutput("Select the printing mode")
+	c.ExpectOutput("1. Colored: Uses more ink")
+	c.ExpectPrompt("Enter your choice")
+	c.Send("1\n")
+	c.Wait()
+}
@@ -9,9 +9,8 @@
 package ask
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
@@ -42,8 +41,10 @@ type NumberStringRune interface {
 // an input request of the supported types (int,string,rune)
 type InputRequest[T NumberStringRune] struct {
 	Prompt  string
+	Help    string // optional help shown when the user types ? or presses F1
 	Default T
 	Value   T
+	ctx     context.Context // the reading is given up when done
 }
 
 /* ----------------------------------------------------------------
@@ -52,23 +53,38 @@ type InputRequest[T NumberStringRune] struct {
 
 // (ctor) request an integer value
 func NewIntInputRequest(prompt string, defval int) *InputRequest[int] {
-	return &InputRequest[int]{prompt, defval, 0}
+	return &InputRequest[int]{Prompt: prompt, Default: defval, Value: 0, ctx: context.Background()}
 }
 
 // (ctor) request a string value
 func NewStringInputRequest(prompt string, defval string) *InputRequest[string] {
-	return &InputRequest[string]{prompt, defval, ""}
+	return &InputRequest[string]{Prompt: prompt, Default: defval, Value: "", ctx: context.Background()}
 }
 
 // (ctor) request a rune value
 func NewRuneInputRequest(prompt string, defval rune) *InputRequest[rune] {
-	return &InputRequest[rune]{prompt, defval, rune(0)}
+	return &InputRequest[rune]{Prompt: prompt, Default: defval, Value: rune(0), ctx: context.Background()}
 }
 
 /* ----------------------------------------------------------------
  *				P u b l i c		M e t h o d s
  *-----------------------------------------------------------------*/
 
+// set the contextual help text that is rendered below the prompt
+// when the user types ? or presses F1.
+func (r *InputRequest[T]) WithHelp(help string) *InputRequest[T] {
+	r.Help = help
+	return r
+}
+
+// give up waiting for the answer when the context is done, for example
+// when the state asking it times out (see fsm.State.WithTimeout). The
+// request then takes its default value.
+func (r *InputRequest[T]) WithContext(ctx context.Context) *InputRequest[T] {
+	r.ctx = ctx
+	return r
+}
+
 // ask for the value. To obtain the answer use any of Answer(),
 // AsInt(), AsRune() or AsString() depending on the value type.
 // to retrieve the value immediately use Read() instead.
@@ -128,28 +144,41 @@ func (r *InputRequest[T]) AsRune() rune {
 
 // read the answer from stdin and return the answer
 // to the caller. The same result can be retrieved later
-// by calling Answer().
+// by calling Answer(). If the request has Help, the
+// user may type ? or press F1 to see it.
 func (r *InputRequest[T]) Read() T {
-	var err error = nil
 	var value T
+	var showHelp func() = nil
+	if len(r.Help) != 0 {
+		showHelp = func() { renderHelp(r.Help) }
+	}
+
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	switch v := any(r.Default).(type) {
 	case int:
 		requestInteger := func() error {
-			var n int
-			fmt.Printf("%s [%d]: ", r.Prompt, v)
-			if n, err = fmt.Scanln(&value); err != nil {
-				if n == 0 {
-					value = r.Default
-					err = nil
-				} else {
-					fmt.Printf("!!! Error reading input: %v\n", err)
-				}
-
+			// at end-of-input (or when given up) an empty or invalid
+			// answer falls back to the default
+			str, readErr := readAnswer(ctx, fmt.Sprintf("%s [%d]: ", r.Prompt, v), showHelp)
+			str = strings.Trim(str, " \t")
+			n, err := strconv.Atoi(str)
+			switch {
+			case len(str) == 0:
+				value = r.Default
+			case err != nil && readErr == nil:
+				fmt.Fprintf(Output(), "!!! Error reading input: %v\n", err)
 				return err
+			case err != nil:
+				value = r.Default
+			default:
+				value = any(n).(T)
 			}
 
 			result, _ := any(value).(int)
-			fmt.Printf("%c %d\n", goask.ICON_WHITE_RIGHT, result)
+			fmt.Fprintf(Output(), "%c %d\n", goask.ICON_WHITE_RIGHT, result)
 			return nil
 		}
 		err := requestInteger()
@@ -158,33 +187,24 @@ func (r *InputRequest[T]) Read() T {
 		}
 
 	case string:
-		fmt.Printf("%s [%s]: ", r.Prompt, v)
-		var str string
-		reader := bufio.NewReader(os.Stdin)
-		str, err = reader.ReadString('\n')
+		str, _ := readAnswer(ctx, fmt.Sprintf("%s [%s]: ", r.Prompt, v), showHelp)
 		if len(strings.Trim(str, " \t\n")) == 0 {
 			value = r.Default
 		} else {
-			str = str[:len(str)-1]
 			value = any(str).(T)
 		}
 		result, _ := any(value).(string)
-		fmt.Printf("%c %s\n", goask.ICON_WHITE_RIGHT, result)
+		fmt.Fprintf(Output(), "%c %s\n", goask.ICON_WHITE_RIGHT, result)
 
 	case rune:
-		fmt.Printf("%s [%c]: ", r.Prompt, v)
-		reader := bufio.NewReader(os.Stdin)
-		str, err := reader.ReadString('\n')
+		str, err := readAnswer(ctx, fmt.Sprintf("%s [%c]: ", r.Prompt, v), showHelp)
 		if len(strings.Trim(str, " \t\n")) == 0 {
 			value = r.Default
-		} else {
-			str = str[:len(str)-1]
-			if err == nil && len(str) > 0 {
-				value = any([]rune(str)[0]).(T)
-			}
+		} else if err == nil && len(str) > 0 {
+			value = any([]rune(str)[0]).(T)
 		}
 		result, _ := any(value).(rune)
-		fmt.Printf("%c %c\n", goask.ICON_WHITE_RIGHT, result)
+		fmt.Fprintf(Output(), "%c %c\n", goask.ICON_WHITE_RIGHT, result)
 
 	default:
 		panic("I don't know that type")
@@ -0,0 +1,32 @@
+package ask_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/asktest"
+)
+
+func TestInputRequestGivenUpKeepsLateAnswer(t *testing.T) {
+	c := asktest.NewConsole(t)
+	var first, second int
+	c.Go(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		first = ask.NewIntInputRequest("Number", 3).WithContext(ctx).Read()
+		second = ask.NewIntInputRequest("Again", 0).Read()
+	})
+	c.ExpectPrompt("Number [3]")
+	// the first question gives up and takes its default
+	c.ExpectPrompt("Again [0]")
+	c.Send("7\n")
+	c.Wait()
+	if first != 3 {
+		t.Errorf("given up: want the default 3 got %d", first)
+	}
+	if second != 7 {
+		t.Errorf("late answer: want 7 got %d", second)
+	}
+}
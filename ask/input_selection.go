@@ -8,9 +8,8 @@
 package ask
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
 	"slices"
 	"strconv"
 
@@ -24,6 +23,7 @@ import (
 type InputSelection struct {
 	Number uint
 	Text   string
+	Help   string // optional description shown when the user asks for help
 }
 
 /* ----------------------------------------------------------------
@@ -46,6 +46,13 @@ func NewInputSelection(nr uint, text string) InputSelection {
  *				P u b l i c		M e t h o d s
  *-----------------------------------------------------------------*/
 
+// returns a copy of the input selection with a description that is
+// shown when the user types ? or presses F1.
+func (is InputSelection) WithHelp(help string) InputSelection {
+	is.Help = help
+	return is
+}
+
 // implements fmt.Stringer
 // the input selection in the form of "Number. Text" suitable for
 // rendering in a menu.
@@ -68,7 +75,8 @@ func (is InputSelection) Chosen() string {
 
 // given the options, show the prompt and enumerate all the options.
 // then use stdin to ask the user until a valid option number is
-// selected.
+// selected. If any of the options has a description, the user may
+// type ? (or press F1) to see them.
 func SelectOptions(prompt string, options []InputSelection) int {
 	if len(options) == 0 {
 		return -1
@@ -86,31 +94,32 @@ func SelectOptions(prompt string, options []InputSelection) int {
 	}
 
 	renderMenu := func() {
-		fmt.Println(goask.ANSI_YELLOW, prompt, goask.ANSI_GREEN)
+		fmt.Fprintln(Output(), goask.ANSI_YELLOW, prompt, goask.ANSI_GREEN)
 		for i, opt := range options {
 			var isDef string = ""
 			if i == 0 {
 				isDef = "(default)"
 			}
-			fmt.Printf("\t%d. %s %s\n", opt.Number, opt.Text, isDef)
+			fmt.Fprintf(Output(), "\t%d. %s %s\n", opt.Number, opt.Text, isDef)
 		}
-		fmt.Print(goask.ANSI_RESET)
+		fmt.Fprint(Output(), goask.ANSI_RESET)
+	}
+
+	var showHelp func() = nil
+	if hasChoicesHelp("", options) {
+		showHelp = func() { renderChoicesHelp("", options) }
 	}
 
 	readSelection := func() int {
-		fmt.Print("Enter your choice: ")
-		reader := bufio.NewReader(os.Stdin)
-		if str, err := reader.ReadString('\n'); err == nil {
-			str = str[:len(str)-1]
-			if len(str) == 0 {
-				return int(options[0].Number)
-			} else if nr, err := strconv.Atoi(str); err != nil {
-				return -1
-			} else {
-				return nr
-			}
+		// at end-of-input the answer falls back to the default
+		str, err := readAnswer(context.Background(), "Enter your choice: ", showHelp)
+		if len(str) == 0 || err != nil {
+			return int(options[0].Number)
+		} else if nr, err := strconv.Atoi(str); err != nil {
+			return -1
+		} else {
+			return nr
 		}
-		return -1
 	}
 
 	selected := -1
@@ -121,6 +130,6 @@ func SelectOptions(prompt string, options []InputSelection) int {
 		}
 	}
 
-	fmt.Printf("%c %d\n", goask.ICON_WHITE_RIGHT, selected)
+	fmt.Fprintf(Output(), "%c %d\n", goask.ICON_WHITE_RIGHT, selected)
 	return selected
 }
@@ -8,9 +8,8 @@
 package ask
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
 	"slices"
 	"strconv"
 
@@ -33,8 +32,10 @@ var _ ICurious = (*QuestionWithChoice)(nil)
 
 type QuestionWithChoice struct {
 	Prompt  string
+	Help    string // optional help shown when the user types ? or presses F1
 	Choices []InputSelection
 	answer  int
+	ctx     context.Context // the reading is given up when done
 }
 
 /* ----------------------------------------------------------------
@@ -50,6 +51,8 @@ func NewMultipleChoiceQuestion(prompt string, choices []InputSelection) *Questio
 	return &QuestionWithChoice{
 		Prompt:  prompt,
 		Choices: choices,
+		answer:  -1,
+		ctx:     context.Background(),
 	}
 }
 
@@ -57,6 +60,22 @@ func NewMultipleChoiceQuestion(prompt string, choices []InputSelection) *Questio
  *				P u b l i c		M e t h o d s
  *-----------------------------------------------------------------*/
 
+// set the contextual help text that is rendered below the prompt
+// when the user types ? or presses F1. The descriptions of the
+// individual choices (InputSelection.Help) are shown along with it.
+func (q *QuestionWithChoice) WithHelp(help string) *QuestionWithChoice {
+	q.Help = help
+	return q
+}
+
+// give up waiting for the choice when the context is done, for example
+// when the menu state asking it times out (see fsm.State.WithTimeout).
+// No choice is then made and AsInt() returns -1.
+func (q *QuestionWithChoice) WithContext(ctx context.Context) *QuestionWithChoice {
+	q.ctx = ctx
+	return q
+}
+
 // implements ask.ICurious and returns the answer.
 func (q *QuestionWithChoice) Answer() any {
 	return q.answer
@@ -64,7 +83,7 @@ func (q *QuestionWithChoice) Answer() any {
 
 // implements ask.ICurious and uses stdin (console) to ask the
 // user to select a valid choice. It keeps on asking until a
-// valid option is chosen.
+// valid option is chosen or the context is done (see WithContext).
 func (q *QuestionWithChoice) Ask() ICurious {
 	q.answer = -1
 	if len(q.Choices) == 0 {
@@ -84,48 +103,58 @@ func (q *QuestionWithChoice) Ask() ICurious {
 	}
 
 	renderMenu := func() {
-		fmt.Println(goask.ANSI_YELLOW, q.Prompt, goask.ANSI_GREEN)
+		fmt.Fprintln(Output(), goask.ANSI_YELLOW, q.Prompt, goask.ANSI_GREEN)
 		for i, opt := range q.Choices {
 			var isDef string = ""
 			if i == 0 {
 				isDef = "(default)"
 			}
-			fmt.Printf("\t%d. %s %s\n", opt.Number, opt.Text, isDef)
+			fmt.Fprintf(Output(), "\t%d. %s %s\n", opt.Number, opt.Text, isDef)
 		}
-		fmt.Print(goask.ANSI_RESET)
+		fmt.Fprint(Output(), goask.ANSI_RESET)
 	}
 
+	var showHelp func() = nil
+	if hasChoicesHelp(q.Help, q.Choices) {
+		showHelp = func() { renderChoicesHelp(q.Help, q.Choices) }
+	}
+
+	ctx := q.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	readSelection := func() int {
-		fmt.Print("Enter your choice: ")
-		reader := bufio.NewReader(os.Stdin)
-		if str, err := reader.ReadString('\n'); err == nil {
-			str = str[:len(str)-1]
-			if len(str) == 0 {
-				return int(q.Choices[0].Number)
-			} else if nr, err := strconv.Atoi(str); err != nil {
-				return -1
-			} else {
-				return nr
-			}
+		// at end-of-input the answer falls back to the default
+		str, err := readAnswer(ctx, "Enter your choice: ", showHelp)
+		if len(str) == 0 || err != nil {
+			return int(q.Choices[0].Number)
+		} else if nr, err := strconv.Atoi(str); err != nil {
+			return -1
+		} else {
+			return nr
 		}
-		return -1
 	}
 
 	selected := -1
 	for selected == -1 {
 		renderMenu()
-		if value := readSelection(); value > -1 && slices.Contains(valid, uint(value)) {
+		value := readSelection()
+		if ctx.Err() != nil {
+			// given up: nothing is chosen
+			return q
+		}
+		if value > -1 && slices.Contains(valid, uint(value)) {
 			selected = value
 		}
 	}
 
 	q.answer = selected
-	fmt.Println(q.Choices[selected].Chosen())
+	fmt.Fprintln(Output(), q.Choices[selected].Chosen())
 	return q
 }
 
 // implements ask.ICurious and returns the value
-// as the chosen option number
+// as the chosen option number, or -1 if none was chosen
 func (q *QuestionWithChoice) AsInt() int {
 	return q.answer
 }
@@ -137,7 +166,10 @@ func (q *QuestionWithChoice) AsRune() rune {
 }
 
 // implements ask.ICurious and returns the text
-// of the chosen answer
+// of the chosen answer, or an empty string if none was chosen
 func (q *QuestionWithChoice) AsString() string {
+	if q.answer < 0 {
+		return ""
+	}
 	return q.Choices[q.answer].Text
 }
@@ -0,0 +1,35 @@
+package ask_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lordofscripts/goask"
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/asktest"
+)
+
+func TestQuestionWithChoiceGivenUp(t *testing.T) {
+	c := asktest.NewConsole(t)
+	question := ask.NewMultipleChoiceQuestion("Pick one", []ask.InputSelection{
+		ask.NewInputSelection(0, "First"),
+		ask.NewInputSelection(1, "Second"),
+	})
+	c.Go(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		question.WithContext(ctx).Ask()
+	})
+	c.Wait()
+	if got := question.AsInt(); got != -1 {
+		t.Errorf("given up: want -1 got %d", got)
+	}
+	if got := question.AsString(); got != "" {
+		t.Errorf("given up: want no text got %q", got)
+	}
+	if out := c.Output(); strings.ContainsRune(out, goask.ICON_WHITE_RIGHT) {
+		t.Errorf("a choice was printed: %q", out)
+	}
+}
@@ -7,7 +7,15 @@
  *-----------------------------------------------------------------*/
 package ask
 
-import "sync/atomic"
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/lordofscripts/goask"
+	"github.com/lordofscripts/goask/tty"
+)
 
 /* ----------------------------------------------------------------
  *						G l o b a l s
@@ -23,13 +31,26 @@ import "sync/atomic"
 
 type Questionaire struct {
 	questions []*SmartQuestion
+	sections  []string // section title of each question (same index)
+	section   string   // section assigned to newly added questions
 	lastId    *atomic.Uint32
+	showSteps bool         // show the "Step N of ~M" counter
+	progress  *progressBar // optional progress bar (nil if none)
+	step      int          // number of the question being asked
+	current   int          // index of the question being asked
+	shown     string       // title of the section shown last
 }
 
 /* ----------------------------------------------------------------
  *				P r i v a t e	T y p e s
  *-----------------------------------------------------------------*/
 
+// where and how the questionaire progress bar is rendered
+type progressBar struct {
+	style tty.ProgressStyle
+	row   int
+}
+
 /* ----------------------------------------------------------------
  *				C o n s t r u c t o r s
  *-----------------------------------------------------------------*/
@@ -39,7 +60,14 @@ func NewQuestionaire() *Questionaire {
 	id.Store(0)
 	return &Questionaire{
 		questions: make([]*SmartQuestion, 0),
+		sections:  make([]string, 0),
+		section:   "",
 		lastId:    &id,
+		showSteps: false,
+		progress:  nil,
+		step:      0,
+		current:   0,
+		shown:     "",
 	}
 }
 
@@ -47,12 +75,45 @@ func NewQuestionaire() *Questionaire {
  *				P u b l i c		M e t h o d s
  *-----------------------------------------------------------------*/
 
+// begin a named section (page) of questions. All questions added
+// after this call belong to that section, and its title is shown
+// when the questionaire reaches the first of them.
+func (qm *Questionaire) BeginSection(title string) *Questionaire {
+	qm.section = title
+	return qm
+}
+
+// show a "Step N of ~M" counter before every question. The total is
+// an estimate along the current branch because the answers to the
+// conditional questions may lead elsewhere.
+func (qm *Questionaire) ShowSteps(enable bool) *Questionaire {
+	qm.showSteps = enable
+	return qm
+}
+
+// show a progress bar of the given style at the given console row
+// before every question. See tty.RenderProgressAt().
+func (qm *Questionaire) ShowProgressBar(style tty.ProgressStyle, row int) *Questionaire {
+	qm.progress = &progressBar{style, row}
+	return qm
+}
+
+// get the current step (1-based number of the question being asked)
+// and the estimated total number of steps along the current branch.
+func (qm *Questionaire) Progress() (int, int) {
+	if qm.step == 0 {
+		return 0, qm.estimateRemaining(0)
+	}
+	return qm.step, qm.step - 1 + qm.estimateRemaining(qm.current)
+}
+
 // add a question and when the answer is obtained, proceed with the
 // next question (AskAndContinue mode)
 func (qm *Questionaire) AddSequential(q ICurious) uint32 {
 	newId := qm.lastId.Add(1)
 	sq := NewSmartQuestion(AskAndContinue, q, nil)
 	qm.questions = append(qm.questions, sq)
+	qm.sections = append(qm.sections, qm.section)
 	return newId
 }
 
@@ -62,6 +123,7 @@ func (qm *Questionaire) AddTerminal(q ICurious) uint32 {
 	newId := qm.lastId.Add(1)
 	sq := NewSmartQuestion(AskAndTerminate, q, nil)
 	qm.questions = append(qm.questions, sq)
+	qm.sections = append(qm.sections, qm.section)
 
 	return newId
 }
@@ -72,6 +134,7 @@ func (qm *Questionaire) AddConditionalChoices(q *QuestionWithChoice, callback An
 	newId := qm.lastId.Add(1)
 	sq := NewSmartQuestion(AskAndDecide, q, callback)
 	qm.questions = append(qm.questions, sq)
+	qm.sections = append(qm.sections, qm.section)
 
 	return newId
 }
@@ -81,6 +144,7 @@ func (qm *Questionaire) AddConditionalSmart(q *SmartQuestion) uint32 {
 	newId := qm.lastId.Add(1)
 	sq := NewSmartQuestion(AskAndDecide, q, q.Callback)
 	qm.questions = append(qm.questions, sq)
+	qm.sections = append(qm.sections, qm.section)
 
 	return newId
 }
@@ -88,39 +152,152 @@ func (qm *Questionaire) AddConditionalSmart(q *SmartQuestion) uint32 {
 // begin the questionaire and terminate when an error occurs or when the
 // last question is asked.
 func (qm *Questionaire) StartQuestionaire() {
-	terminate := false
-	nextIdx := 0
-
-	for !terminate {
-		// get the current question
-		question := qm.questions[nextIdx]
-		// ask interactively and get the answer
-		question.Ask()
-		// decide what to do next
-		switch question.Mode {
-		// proceed sequentially with the next in the list
-		case AskAndContinue:
-			nextIdx++
-
-		case AskAndDecide:
-			nextQ := question.Next()
-			nextIdx = int(nextQ.GetId())
-
-		// terminate the questionaire
-		case AskAndTerminate:
-			terminate = true
+	qm.Reset()
+	for next := 0; next >= 0; {
+		next = qm.AskAt(next)
+	}
+}
+
+// forget the progress made so that the questionaire can be started
+// again from the first question.
+func (qm *Questionaire) Reset() {
+	qm.step = 0
+	qm.current = 0
+	qm.shown = ""
+}
+
+// get the number of questions in the questionaire
+func (qm *Questionaire) Len() int {
+	return len(qm.questions)
+}
+
+// get the question at index (0-based). The id returned by the Add
+// methods is index+1.
+func (qm *Questionaire) Question(index int) *SmartQuestion {
+	return qm.questions[index]
+}
+
+// get the index of a question, which may be a question returned by an
+// AnswerCallback: either the SmartQuestion given to AddConditionalSmart
+// or one that wraps a question that was added. -1 if not found.
+func (qm *Questionaire) IndexOf(q ICuriouslySmart) int {
+	if other, ok := q.(*SmartQuestion); q == nil || (ok && other == nil) {
+		return -1
+	}
+	for i, sq := range qm.questions {
+		if sq == q || sq.Question == q {
+			return i
+		}
+		if other, ok := q.(*SmartQuestion); ok && sq.Question == other.Question {
+			return i
+		}
+	}
+	return -1
+}
+
+// ask the question at index (0-based) showing its section and progress
+// first, and return the index of the next question: the one that
+// follows, the one chosen by the callback of a conditional question,
+// or -1 when the questionaire is over. This is a single step of
+// StartQuestionaire.
+func (qm *Questionaire) AskAt(index int) int {
+	question := qm.questions[index]
+	qm.step++
+	qm.current = index
+	// show where we are
+	if section := qm.sections[index]; section != qm.shown {
+		renderSection(section)
+		qm.shown = section
+	}
+	qm.renderProgress()
+	// ask interactively and get the answer
+	question.Ask()
+	// decide what to do next
+	next := -1
+	switch question.Mode {
+	// proceed sequentially with the next in the list
+	case AskAndContinue:
+		if index+1 < len(qm.questions) {
+			next = index + 1
 		}
+
+	case AskAndDecide:
+		next = qm.IndexOf(question.Next())
+
+	// terminate the questionaire
+	case AskAndTerminate:
+		next = -1
+	}
+
+	if next < 0 && qm.progress != nil {
+		tty.RenderProgressAt(Output(), qm.progress.style, qm.progress.row, qm.sections[index], 100)
 	}
+	return next
 }
 
 /* ----------------------------------------------------------------
  *				P r i v a t e	M e t h o d s
  *-----------------------------------------------------------------*/
 
+// estimate how many questions remain (including the one at index)
+// assuming every conditional question continues with the next one
+// in the list, until a terminal question is reached.
+func (qm *Questionaire) estimateRemaining(index int) int {
+	count := 0
+	for i := index; i < len(qm.questions); i++ {
+		count++
+		if qm.questions[i].Mode == AskAndTerminate {
+			break
+		}
+	}
+	return count
+}
+
+// whether the questionaire has conditional questions, in which case
+// the total number of steps is only an estimate.
+func (qm *Questionaire) isBranching() bool {
+	for _, q := range qm.questions {
+		if q.Mode == AskAndDecide {
+			return true
+		}
+	}
+	return false
+}
+
+// render the step counter and/or the progress bar of the question
+// that is about to be asked.
+func (qm *Questionaire) renderProgress() {
+	step, total := qm.Progress()
+	if qm.progress != nil {
+		percent := uint(0)
+		if total > 0 {
+			percent = uint((step - 1) * 100 / total)
+		}
+		tty.RenderProgressAt(Output(), qm.progress.style, qm.progress.row, qm.sections[qm.current], percent)
+	}
+
+	if qm.showSteps {
+		approx := ""
+		if qm.isBranching() {
+			approx = "~"
+		}
+		fmt.Fprintf(Output(), "%sStep %d of %s%d%s\n", goask.ANSI_BROWN, step, approx, total, goask.ANSI_RESET)
+	}
+}
+
 /* ----------------------------------------------------------------
  *					F u n c t i o n s
  *-----------------------------------------------------------------*/
 
+// render the title of a section (page) of questions
+func renderSection(title string) {
+	if len(title) == 0 {
+		return
+	}
+	ruler := strings.Repeat("=", utf8.RuneCountInString(title)+2)
+	fmt.Fprintf(Output(), "%s%s\n %s\n%s%s\n", goask.ANSI_YELLOW, ruler, title, ruler, goask.ANSI_RESET)
+}
+
 /* ----------------------------------------------------------------
  *						T e s t s
  *-----------------------------------------------------------------*/
@@ -0,0 +1,111 @@
+package ask_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/asktest"
+	"github.com/lordofscripts/goask/tty"
+)
+
+func init() { asktest.RegisterUpdateFlag() }
+
+// a questionaire of two sections whose last question branches to either
+// a terminal question or the end of the list
+func newSectionedQuestionaire() *ask.Questionaire {
+	qm := ask.NewQuestionaire()
+	qm.BeginSection("Personal")
+	qm.AddSequential(ask.NewStringInputRequest("Name", "nobody"))
+	qm.AddSequential(ask.NewIntInputRequest("Age", 0))
+	qm.BeginSection("Order")
+	qm.AddConditionalChoices(ask.NewMultipleChoiceQuestion("Gift wrap?", []ask.InputSelection{
+		ask.NewInputSelection(0, "No"),
+		ask.NewInputSelection(1, "Yes"),
+	}), func(uint32) *ask.SmartQuestion { return qm.Question(4) })
+	qm.AddTerminal(ask.NewStringInputRequest("Skipped", ""))
+	qm.AddSequential(ask.NewStringInputRequest("Card text", ""))
+	return qm
+}
+
+func TestQuestionaireSectionsAndSteps(t *testing.T) {
+	qm := newSectionedQuestionaire().ShowSteps(true)
+	out := asktest.RenderFunc(qm.StartQuestionaire, "Ann\n30\n1\nCheers\n")
+	for _, section := range []string{" Personal\n", " Order\n"} {
+		if got := strings.Count(out, section); got != 1 {
+			t.Errorf("section %q rendered %d times", section, got)
+		}
+	}
+	asktest.AssertGoldenANSI(t, "questionaire-steps", out)
+}
+
+func TestQuestionaireProgress(t *testing.T) {
+	qm := newSectionedQuestionaire()
+	if step, total := qm.Progress(); step != 0 || total != 4 {
+		t.Errorf("before the first question: want 0 of 4 got %d of %d", step, total)
+	}
+
+	var steps [][2]int
+	asktest.RenderFunc(func() {
+		qm.Reset()
+		for next := 0; next >= 0; {
+			next = qm.AskAt(next)
+			step, total := qm.Progress()
+			steps = append(steps, [2]int{step, total})
+		}
+	}, "Ann\n30\n1\nCheers\n")
+
+	// the estimate follows the list until the terminal question, then
+	// the branch taken
+	want := [][2]int{{1, 4}, {2, 4}, {3, 4}, {4, 4}}
+	if len(steps) != len(want) {
+		t.Fatalf("steps: want %v got %v", want, steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Fatalf("steps: want %v got %v", want, steps)
+		}
+	}
+}
+
+func TestQuestionaireEndOfList(t *testing.T) {
+	qm := ask.NewQuestionaire()
+	qm.AddSequential(ask.NewStringInputRequest("Only", ""))
+	var next int
+	asktest.RenderFunc(func() { next = qm.AskAt(0) }, "\n")
+	if next != -1 {
+		t.Errorf("after the last question: want -1 got %d", next)
+	}
+}
+
+func TestQuestionaireProgressBar(t *testing.T) {
+	qm := newSectionedQuestionaire().ShowProgressBar(tty.ProgressStyle1, 2)
+	out := asktest.RenderFunc(qm.StartQuestionaire, "Ann\n30\n0\n")
+	for _, want := range []string{
+		"\033[s",    // the cursor is saved
+		"\033[2;1H", // the bar is drawn at its row
+		"00%/100%      Personal\033[K",
+		"50%/100%      Order\033[K",
+		"100%/100% ✓ OK Order\033[K",
+		"\033[u", // and the cursor restored
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in the output:\n%q", want, out)
+		}
+	}
+}
+
+func TestQuestionaireCallbackReturnsNil(t *testing.T) {
+	qm := ask.NewQuestionaire()
+	qm.AddConditionalChoices(ask.NewMultipleChoiceQuestion("Continue?", []ask.InputSelection{
+		ask.NewInputSelection(0, "No"),
+		ask.NewInputSelection(1, "Yes"),
+	}), func(uint32) *ask.SmartQuestion { return nil })
+	qm.AddSequential(ask.NewStringInputRequest("Name", "nobody"))
+
+	out := asktest.RenderFunc(qm.StartQuestionaire, "1\n")
+	if got := ask.NewQuestionaire().IndexOf((*ask.SmartQuestion)(nil)); got != -1 {
+		t.Errorf("index of nil: want -1 got %d", got)
+	}
+	asktest.AssertGoldenANSI(t, "questionaire-nil-callback", out)
+}
@@ -79,7 +79,10 @@ func (q *SmartQuestion) Type() AnswerType {
 
 func (q *SmartQuestion) Next() ICuriouslySmart {
 	if q.Callback != nil {
-		return q.Callback(uint32(q.Question.AsInt()))
+		// a nil *SmartQuestion must not become a non-nil interface
+		if next := q.Callback(uint32(q.Question.AsInt())); next != nil {
+			return next
+		}
 	}
 	return nil
 }
@@ -0,0 +1,92 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Menu states in declarative state machine definitions: a state of
+ * kind "menu" is built from its prompt and options.
+ *-----------------------------------------------------------------*/
+package askfsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lordofscripts/goask/fsm"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// the kind of the menu states in a definition (see RegisterMenuKind)
+	MenuKind = "menu"
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// The params of a state of kind "menu", for example:
+//
+//	{ "id": 3, "name": "ST", "kind": "menu",
+//	  "params": { "prompt": "Select Technical Support area?",
+//	    "options": [ { "text": "Cancel", "target": 1 },
+//	                 { "text": "Internet", "target": 5 } ] } }
+type MenuDefinition struct {
+	Prompt  string                 `json:"prompt"`
+	Options []MenuOptionDefinition `json:"options"`
+}
+
+// An option of a menu state (see MenuOption). The action is given by
+// its name in the registry (see fsm.Registry.RegisterAction) and it is
+// called with the text of the option as payload.
+type MenuOptionDefinition struct {
+	Text   string      `json:"text"`
+	Target fsm.StateId `json:"target"`
+	Action string      `json:"action,omitempty"`
+	Help   string      `json:"help,omitempty"`
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// register the "menu" kind of state so that the menus of a state
+// machine can be defined as data (see fsm.NewStateMachineFromJSON).
+func RegisterMenuKind(registry *fsm.Registry) *fsm.Registry {
+	return registry.RegisterKind(MenuKind, buildMenuState)
+}
+
+// build a menu state from its definition
+func buildMenuState(def fsm.StateDefinition, registry *fsm.Registry) (*fsm.State, error) {
+	var menu MenuDefinition
+	dec := json.NewDecoder(bytes.NewReader(def.Params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&menu); err != nil {
+		return nil, fmt.Errorf("menu params: %w", err)
+	}
+	if len(menu.Options) == 0 {
+		return nil, errors.New("menu has no options")
+	}
+
+	options := make([]MenuOption, 0, len(menu.Options))
+	problems := make([]error, 0)
+	for _, od := range menu.Options {
+		action, err := registry.Action(od.Action)
+		problems = append(problems, err)
+		option := MenuOption{Text: od.Text, Target: od.Target, Action: nil, Help: od.Help}
+		if action != nil {
+			text := od.Text
+			option.Action = func(sm fsm.IStateMachine) { action(sm, text) }
+		}
+		options = append(options, option)
+	}
+	if err := errors.Join(problems...); err != nil {
+		return nil, err
+	}
+	return NewMenuState(def.Id, def.Name, menu.Prompt, options), nil
+}
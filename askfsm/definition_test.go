@@ -0,0 +1,59 @@
+package askfsm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goask/asktest"
+	"github.com/lordofscripts/goask/fsm"
+)
+
+const menuDefinition = `{
+  "name": "Support",
+  "states": [
+    { "id": 1, "name": "Menu", "kind": "menu",
+      "params": { "prompt": "Which area?",
+        "options": [ { "text": "Cancel", "target": 3 },
+                     { "text": "Internet", "target": 2, "action": "log" } ] } },
+    { "id": 2, "name": "Done", "terminal": true },
+    { "id": 3, "name": "Hung up", "terminal": true }
+  ]
+}`
+
+func TestMenuKindDefinition(t *testing.T) {
+	var chosen any
+	registry := RegisterMenuKind(fsm.NewRegistry()).
+		RegisterAction("log", func(_ fsm.IStateMachine, payload any) { chosen = payload })
+	sm, err := fsm.NewStateMachineFromJSON[any](strings.NewReader(menuDefinition), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.GetInitial().TransitionLabel(2); got != "Internet" {
+		t.Errorf("transition label: want %q got %q", "Internet", got)
+	}
+
+	c := asktest.NewConsole(t)
+	c.Go(func() { err = sm.Start() })
+	c.ExpectOutput("Which area?")
+	c.ExpectPrompt("Enter your choice")
+	c.Send("1\n")
+	c.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != 2 {
+		t.Errorf("current state: want 2 got %d", got)
+	}
+	if chosen != "Internet" {
+		t.Errorf("action payload: want %q got %v", "Internet", chosen)
+	}
+}
+
+func TestMenuKindUnknownAction(t *testing.T) {
+	registry := RegisterMenuKind(fsm.NewRegistry())
+	_, err := fsm.NewStateMachineFromJSON[any](strings.NewReader(menuDefinition), registry)
+	if !errors.Is(err, fsm.ErrUnknownHandler) {
+		t.Errorf("want %v got %v", fsm.ErrUnknownHandler, err)
+	}
+}
@@ -0,0 +1,83 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Bridge between the ask and fsm packages: menu states that ask a
+ * multiple choice question and transition according to the answer.
+ *-----------------------------------------------------------------*/
+package askfsm
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/fsm"
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// An option of a menu state: its text, the state it transitions to
+// and an optional side effect executed when it is chosen.
+type MenuOption struct {
+	Text   string
+	Target fsm.StateId
+	Action func(fsm.IStateMachine) // optional
+	Help   string                  // optional, see ask.InputSelection.WithHelp
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) Creates a state that asks a multiple choice question with the
+// options numbered from 0 (the first one being the default). The chosen
+// option's Action is executed, its text recorded as the cause of the
+// transition (see IStateMachine.SetCause) and the state transitions to
+// its Target. The transitions to the targets are declared, labeled
+// with the texts of the options that lead there (joined by " / "), so
+// that validating the machine rejects the options whose target is not
+// one of its states. The question gives up when the Context() of the
+// machine is done, e.g. when the state times out (see
+// fsm.State.WithTimeout), and then no option is chosen.
+func NewMenuState(id fsm.StateId, name string, prompt string, options []MenuOption) *fsm.State {
+	choices := make([]ask.InputSelection, 0, len(options))
+	for i, option := range options {
+		choices = append(choices, ask.NewInputSelection(uint(i), option.Text).WithHelp(option.Help))
+	}
+
+	state := fsm.NewStateSimple(id, name, false, func(sm fsm.IStateMachine) fsm.StateId {
+		question := ask.NewMultipleChoiceQuestion(prompt, choices).WithContext(sm.Context())
+		choice := question.Ask().AsInt()
+		if sm.Context().Err() != nil {
+			// given up, e.g. timed out: nothing was chosen
+			return id
+		}
+		if choice < 0 || choice >= len(options) {
+			return fsm.StateNone
+		}
+
+		option := options[choice]
+		if option.Action != nil {
+			option.Action(sm)
+		}
+		sm.SetCause(option.Text)
+		return option.Target
+	})
+
+	labels := make(map[fsm.StateId][]string)
+	targets := make([]fsm.StateId, 0, len(options))
+	for _, option := range options {
+		if !slices.Contains(targets, option.Target) {
+			targets = append(targets, option.Target)
+		}
+		labels[option.Target] = append(labels[option.Target], option.Text)
+	}
+	for _, target := range targets {
+		state.AllowTransition(target, strings.Join(labels[target], " / "))
+	}
+	return state
+}
@@ -0,0 +1,84 @@
+package askfsm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lordofscripts/goask"
+	"github.com/lordofscripts/goask/asktest"
+	"github.com/lordofscripts/goask/fsm"
+)
+
+const (
+	menuState fsm.StateId = iota + 1
+	doneState
+	hungUpState
+)
+
+func newTimedMachine(menu *fsm.State) *fsm.StateMachine[int] {
+	return fsm.NewStateMachine[int]("timed", menu,
+		fsm.NewStateSimple(doneState, "Done", true, nil),
+		fsm.NewStateSimple(hungUpState, "Hung up", true, nil))
+}
+
+func TestMenuStateChoice(t *testing.T) {
+	c := asktest.NewConsole(t)
+	sm := newTimedMachine(NewMenuState(menuState, "Menu", "Pick one", []MenuOption{
+		{Text: "Hang up", Target: hungUpState},
+		{Text: "Done", Target: doneState},
+	}))
+
+	var err error
+	c.Go(func() { err = sm.Start() })
+	c.ExpectPrompt("Enter your choice")
+	c.Send("1\n")
+	c.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != doneState {
+		t.Errorf("current state: want %d got %d", doneState, got)
+	}
+}
+
+func TestMenuStateTimeoutEscalates(t *testing.T) {
+	c := asktest.NewConsole(t)
+	waited := 0
+	menu := NewMenuState(menuState, "Menu", "Pick one", []MenuOption{
+		{Text: "Wait", Target: menuState, Action: func(fsm.IStateMachine) { waited++ }},
+		{Text: "Done", Target: doneState},
+	})
+	menu.WithTimeout(50*time.Millisecond, menuState).MaxTimeouts(3, hungUpState)
+	sm := newTimedMachine(menu)
+
+	var err error
+	c.Go(func() { err = sm.Start() })
+	c.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != hungUpState {
+		t.Errorf("current state: want %d got %d", hungUpState, got)
+	}
+	if waited != 0 {
+		t.Errorf("the default option was taken %d times on timeout", waited)
+	}
+	if out := c.Output(); strings.ContainsRune(out, goask.ICON_WHITE_RIGHT) {
+		t.Errorf("a choice was printed on timeout: %q", out)
+	}
+}
+
+func TestMenuStateLabelsSharedTarget(t *testing.T) {
+	menu := NewMenuState(menuState, "Menu", "Pick one", []MenuOption{
+		{Text: "Cancel", Target: hungUpState},
+		{Text: "Internet", Target: doneState},
+		{Text: "Phone", Target: doneState},
+	})
+	if got := menu.Transitions(); len(got) != 2 {
+		t.Errorf("transitions: want 2 got %v", got)
+	}
+	if got := menu.TransitionLabel(doneState); got != "Internet / Phone" {
+		t.Errorf("label: want %q got %q", "Internet / Phone", got)
+	}
+}
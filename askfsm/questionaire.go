@@ -0,0 +1,104 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Questionaires compiled into state machines so that they run on the
+ * same engine as any other state machine.
+ *-----------------------------------------------------------------*/
+package askfsm
+
+import (
+	"fmt"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/fsm"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// the terminal state of a compiled questionaire
+	QuestionaireEnd fsm.StateId = fsm.StateFinal
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// The optional OnEnter and OnExit handlers of the state of a question in
+// a compiled questionaire (see NewQuestionaireMachine).
+type QuestionHooks struct {
+	Question uint32 // the id returned when the question was added
+	OnEnter  fsm.OnEnterHandler
+	OnExit   fsm.OnExitHandler
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) Compiles a questionaire into a state machine with one state per
+// question, whose id is the one returned when the question was added
+// (see ask.Questionaire.AddSequential) and named Q1, Q2 and so on, plus
+// a terminal QuestionaireEnd state. Each state asks its question (see
+// ask.Questionaire.AskAt) and transitions to the next question:
+//   - sequential questions declare the transition to the next one (or
+//     to the end if it is the last one).
+//   - terminal questions declare the transition to the end.
+//   - conditional questions transition to the question their callback
+//     returns (or to the end if none), which can not be declared.
+//
+// The hooks, if any, are attached to the states of their questions;
+// those of unknown questions are ignored. The questionaire is rewound
+// whenever the machine starts.
+func NewQuestionaireMachine[T any](name string, qm *ask.Questionaire, hooks ...QuestionHooks) *fsm.StateMachine[T] {
+	hooksOf := make(map[fsm.StateId]QuestionHooks, len(hooks))
+	for _, h := range hooks {
+		hooksOf[fsm.StateId(h.Question)] = h
+	}
+
+	end := fsm.NewStateSimple(QuestionaireEnd, "End", true, nil)
+	states := make([]*fsm.State, 0, qm.Len()+1)
+	for index := 0; index < qm.Len(); index++ {
+		id := questionState(index)
+		h := hooksOf[id]
+		state := fsm.NewState(id, fmt.Sprintf("Q%d", index+1), h.OnEnter, h.OnExit, false, func(fsm.IStateMachine) fsm.StateId {
+			return questionState(qm.AskAt(index))
+		})
+
+		switch qm.Question(index).Mode {
+		case ask.AskAndContinue:
+			if index+1 < qm.Len() {
+				state.AllowTransitions(questionState(index + 1))
+			} else {
+				state.AllowTransitions(QuestionaireEnd)
+			}
+		case ask.AskAndTerminate:
+			state.AllowTransitions(QuestionaireEnd)
+		}
+		states = append(states, state)
+	}
+
+	var initial *fsm.State = nil
+	if len(states) != 0 {
+		initial, states = states[0], states[1:]
+	}
+	sm := fsm.NewStateMachine[T](name, initial, append(states, end)...)
+	sm.OnStart(func(fsm.IStateMachine) { qm.Reset() })
+	return sm
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// the state of the question at index, or the end if the index is -1
+func questionState(index int) fsm.StateId {
+	if index < 0 {
+		return QuestionaireEnd
+	}
+	return fsm.StateId(index + 1)
+}
@@ -0,0 +1,131 @@
+package askfsm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/asktest"
+	"github.com/lordofscripts/goask/fsm"
+)
+
+// a questionaire whose conditional question ends it by returning nil
+func newEndingQuestionaire() *ask.Questionaire {
+	qm := ask.NewQuestionaire()
+	qm.AddConditionalChoices(ask.NewMultipleChoiceQuestion("Continue?", []ask.InputSelection{
+		ask.NewInputSelection(0, "No"),
+		ask.NewInputSelection(1, "Yes"),
+	}), func(uint32) *ask.SmartQuestion { return nil })
+	qm.AddSequential(ask.NewStringInputRequest("Name", "nobody"))
+	return qm
+}
+
+// a questionaire whose conditional question skips the terminal one when
+// the answer is Yes
+func newBranchingQuestionaire() *ask.Questionaire {
+	qm := ask.NewQuestionaire()
+	qm.AddSequential(ask.NewStringInputRequest("Name", "nobody"))
+	qm.AddConditionalChoices(ask.NewMultipleChoiceQuestion("Gift wrap?", []ask.InputSelection{
+		ask.NewInputSelection(0, "No"),
+		ask.NewInputSelection(1, "Yes"),
+	}), func(answer uint32) *ask.SmartQuestion {
+		if answer == 1 {
+			return qm.Question(3)
+		}
+		return qm.Question(2)
+	})
+	qm.AddTerminal(ask.NewStringInputRequest("Reason", ""))
+	qm.AddSequential(ask.NewStringInputRequest("Card text", ""))
+	return qm
+}
+
+func TestQuestionaireMachineCallbackReturnsNil(t *testing.T) {
+	c := asktest.NewConsole(t)
+	sm := NewQuestionaireMachine[any]("ending", newEndingQuestionaire())
+
+	var err error
+	c.Go(func() { err = sm.Start() })
+	c.ExpectPrompt("Enter your choice")
+	c.Send("1\n")
+	c.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != QuestionaireEnd {
+		t.Errorf("current state: want %d got %d", QuestionaireEnd, got)
+	}
+}
+
+func TestQuestionaireMachineDeclaredTransitions(t *testing.T) {
+	sm := NewQuestionaireMachine[any]("branching", newBranchingQuestionaire())
+	if err := sm.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[fsm.StateId][]fsm.StateId{
+		1:               {2},               // sequential: the next question
+		2:               nil,               // conditional: not declared
+		3:               {QuestionaireEnd}, // terminal
+		4:               {QuestionaireEnd}, // sequential: the last question
+		QuestionaireEnd: nil,
+	}
+	states := sm.States()
+	if len(states) != len(want) {
+		t.Fatalf("states: want %d got %d", len(want), len(states))
+	}
+	for _, state := range states {
+		if got := state.Transitions(); fmt.Sprint(got) != fmt.Sprint(want[state.Id]) {
+			t.Errorf("%s: want %v got %v", state, want[state.Id], got)
+		}
+	}
+	if got := states[0].Name; got != "Q1" {
+		t.Errorf("name: want Q1 got %q", got)
+	}
+}
+
+func TestQuestionaireMachineRuns(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  []fsm.StateId
+	}{
+		{"sequential and terminal", "Ann\n0\nbusy\n", []fsm.StateId{1, 2, 3, QuestionaireEnd}},
+		{"branching", "Ann\n1\nCheers\n", []fsm.StateId{1, 2, 4, QuestionaireEnd}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var entered []fsm.StateId
+			sm := NewQuestionaireMachine[any]("branching", newBranchingQuestionaire())
+			sm.OnStateEnter(func(_ fsm.IStateMachine, id fsm.StateId) { entered = append(entered, id) })
+
+			var err error
+			asktest.RenderFunc(func() { err = sm.Start() }, tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(entered) != fmt.Sprint(tc.want) {
+				t.Errorf("states: want %v got %v", tc.want, entered)
+			}
+		})
+	}
+}
+
+func TestQuestionaireMachineHooks(t *testing.T) {
+	var calls []string
+	record := func(call string) func(fsm.IStateMachine) {
+		return func(fsm.IStateMachine) { calls = append(calls, call) }
+	}
+	sm := NewQuestionaireMachine[any]("hooked", newBranchingQuestionaire(),
+		QuestionHooks{Question: 1, OnExit: record("exit Name")},
+		QuestionHooks{Question: 4, OnEnter: record("enter Card text"), OnExit: record("exit Card text")},
+		QuestionHooks{Question: 9, OnEnter: record("unknown")})
+
+	var err error
+	asktest.RenderFunc(func() { err = sm.Start() }, "Ann\n1\nCheers\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"exit Name", "enter Card text", "exit Card text"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("hooks: want %v got %v", want, calls)
+	}
+}
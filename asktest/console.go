@@ -0,0 +1,285 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Expect-style virtual console to unit-test interactive questions:
+ * wait for a prompt, send the answer, check what was rendered.
+ *-----------------------------------------------------------------*/
+package asktest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lordofscripts/goask/ask"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// how long an expectation waits unless changed with WithTimeout
+	DefaultTimeout = 2 * time.Second
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// A virtual console that replaces the ask console for the duration of
+// a test. The interaction under test runs in the background (see Ask
+// and Go) while the test expects its output and sends it input:
+//
+//	c := asktest.NewConsole(t)
+//	c.Ask(ask.NewIntInputRequest("Enter integer", 0))
+//	c.ExpectPrompt("Enter integer")
+//	c.Send("42\n")
+//	c.ExpectOutput("👉 42")
+//	c.Wait()
+//
+// The console is captured (see ask.CaptureConsole) until the test ends,
+// so other virtual consoles and scripted runs wait meanwhile.
+type Console struct {
+	t        testing.TB
+	input    *io.PipeWriter
+	output   *buffer
+	consumed int // bytes of the output already matched
+	timeout  time.Duration
+	done     chan struct{}
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// the output of the console, which signals every write
+type buffer struct {
+	mu      sync.Mutex
+	data    []byte
+	changed chan struct{}
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) a virtual console that is the ask console until the test ends
+func NewConsole(t testing.TB) *Console {
+	t.Helper()
+	reader, writer := io.Pipe()
+	c := &Console{
+		t:        t,
+		input:    writer,
+		output:   &buffer{data: make([]byte, 0), changed: make(chan struct{}, 1)},
+		consumed: 0,
+		timeout:  DefaultTimeout,
+		done:     nil,
+	}
+
+	restore := ask.CaptureConsole(reader, c.output)
+	t.Cleanup(func() {
+		// at end-of-input whatever is still asking takes its default
+		writer.Close()
+		c.waitDone()
+		restore()
+	})
+	return c
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// set how long the expectations wait
+func (c *Console) WithTimeout(timeout time.Duration) *Console {
+	c.timeout = timeout
+	return c
+}
+
+// start asking a question in the background
+func (c *Console) Ask(question ask.ICurious) *Console {
+	return c.Go(func() { question.Ask() })
+}
+
+// start any interaction in the background, e.g. a questionaire or a
+// state machine. Only one interaction may run at a time.
+func (c *Console) Go(interaction func()) *Console {
+	c.t.Helper()
+	if c.done != nil {
+		c.t.Fatal("asktest: an interaction is already running")
+	}
+	done := make(chan struct{})
+	c.done = done
+	go func() {
+		defer close(done)
+		interaction()
+	}()
+	return c
+}
+
+// wait for the output to contain the text
+func (c *Console) ExpectOutput(text string) {
+	c.t.Helper()
+	if err := c.expect(text, false); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// wait for a prompt containing the text, that is, the text is on the
+// last line of the output which is waiting for an answer.
+func (c *Console) ExpectPrompt(text string) {
+	c.t.Helper()
+	if err := c.expect(text, true); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// send input, e.g. an answer followed by "\n"
+func (c *Console) Send(input string) {
+	c.t.Helper()
+	sent := make(chan error, 1)
+	go func() {
+		_, err := io.WriteString(c.input, input)
+		sent <- err
+	}()
+
+	select {
+	case err := <-sent:
+		if err != nil {
+			c.t.Fatalf("asktest: sending %q: %v", input, err)
+		}
+	case <-time.After(c.timeout):
+		c.t.Fatalf("asktest: nobody read %q within %v\n%s", input, c.timeout, c.pending())
+	}
+}
+
+// wait for the interaction started by Ask or Go to finish
+func (c *Console) Wait() {
+	c.t.Helper()
+	if c.done == nil {
+		return
+	}
+	select {
+	case <-c.done:
+		c.done = nil
+	case <-time.After(c.timeout):
+		c.t.Fatalf("asktest: the interaction did not finish within %v\n%s", c.timeout, c.pending())
+	}
+}
+
+// get all the output rendered so far
+func (c *Console) Output() string {
+	return c.output.String()
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// wait for the text to appear in the output not yet matched, and mark
+// the output up to the end of the text as matched.
+func (c *Console) expect(text string, prompt bool) error {
+	deadline := time.NewTimer(c.timeout)
+	defer deadline.Stop()
+
+	kind := "output"
+	if prompt {
+		kind = "prompt"
+	}
+	for {
+		pending := c.output.String()[c.consumed:]
+		if idx := strings.Index(pending, text); idx > -1 {
+			rest := pending[idx+len(text):]
+			if !prompt || !strings.Contains(rest, "\n") {
+				c.consumed += idx + len(text)
+				return nil
+			}
+		}
+
+		select {
+		case <-c.output.changed:
+		case <-deadline.C:
+			return fmt.Errorf("asktest: expected %s not seen within %v\n%s", kind, c.timeout, diff(text, pending))
+		}
+	}
+}
+
+// the output not yet matched, for messages
+func (c *Console) pending() string {
+	return fmt.Sprintf("pending output: %q", c.output.String()[c.consumed:])
+}
+
+// wait a moment for the interaction to finish after end-of-input
+func (c *Console) waitDone() {
+	if c.done == nil {
+		return
+	}
+	select {
+	case <-c.done:
+	case <-time.After(c.timeout):
+	}
+}
+
+// implements io.Writer
+func (b *buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	b.data = append(b.data, p...)
+	b.mu.Unlock()
+
+	select {
+	case b.changed <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// implements fmt.Stringer
+func (b *buffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return string(b.data)
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// a readable comparison of the expected text and the output: the part
+// of the output that matches the longest prefix of the text is lined
+// up with it and the first difference is marked.
+func diff(want, got string) string {
+	best, at := 0, len(got)
+	for start := 0; start < len(got); start++ {
+		n := commonPrefix(want, got[start:])
+		if n > best {
+			best, at = n, start
+		}
+	}
+
+	wantQ := fmt.Sprintf("%q", want)
+	if best == 0 {
+		return fmt.Sprintf("want: %s\n got: %q", wantQ, got)
+	}
+	end := min(len(got), at+len(want)+10)
+	gotQ := fmt.Sprintf("%q", got[at:end])
+	marker := utf8.RuneCountInString(fmt.Sprintf("%q", want[:best])) - 1
+	return fmt.Sprintf("want: %s\n got: %s (at byte %d of %d)\n      %s^ first difference",
+		wantQ, gotQ, at, len(got), strings.Repeat(" ", marker))
+}
+
+// the number of leading bytes a and b have in common
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
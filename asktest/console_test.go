@@ -0,0 +1,78 @@
+package asktest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lordofscripts/goask/ask"
+)
+
+func TestConsoleInputRequest(t *testing.T) {
+	c := NewConsole(t)
+	question := ask.NewIntInputRequest("Enter integer", 0)
+	c.Ask(question)
+	c.ExpectPrompt("Enter integer [0]: ")
+	c.Send("42\n")
+	c.ExpectOutput("👉 42")
+	c.Wait()
+	if question.Value != 42 {
+		t.Errorf("answer: want 42 got %d", question.Value)
+	}
+}
+
+func TestConsoleHelp(t *testing.T) {
+	c := NewConsole(t)
+	c.Ask(ask.NewStringInputRequest("Name", "nobody").WithHelp("Your given name"))
+	c.ExpectPrompt("Name [nobody]: ")
+	c.Send("?\n")
+	c.ExpectOutput("Your given name")
+	c.ExpectPrompt("Name [nobody]: ")
+	c.Send("Why?\n")
+	c.ExpectOutput("👉 Why?")
+	c.Wait()
+}
+
+func TestConsolePromptIsLastLine(t *testing.T) {
+	c := NewConsole(t).WithTimeout(100 * time.Millisecond)
+	c.Go(func() { fmt.Fprint(ask.Output(), "Hello\nworld") })
+	c.Wait()
+	if err := c.expect("Hello", true); err == nil {
+		t.Error("a line that was terminated was taken as the prompt")
+	}
+	c.ExpectPrompt("world")
+}
+
+func TestConsoleMismatch(t *testing.T) {
+	c := NewConsole(t).WithTimeout(100 * time.Millisecond)
+	c.Ask(ask.NewIntInputRequest("Enter integer", 0))
+	err := c.expect("Enter number", true)
+	if err == nil {
+		t.Fatal("a prompt that was not rendered was seen")
+	}
+	for _, want := range []string{"expected prompt not seen within 100ms", `want: "Enter number"`, "^ first difference"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("the error does not contain %q:\n%v", want, err)
+		}
+	}
+	c.Send("\n")
+	c.Wait()
+}
+
+func TestDiff(t *testing.T) {
+	lines := strings.Split(diff("Enter integer", "xx Enter intejer [0]: "), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines got:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[1], "(at byte 3 of 22)") {
+		t.Errorf("match position: %s", lines[1])
+	}
+	if marker, differs := strings.Index(lines[2], "^"), strings.Index(lines[0], "g"); marker != differs {
+		t.Errorf("the marker is at %d instead of %d:\n%s", marker, differs, strings.Join(lines, "\n"))
+	}
+
+	if got := diff("Enter", "nothing alike"); !strings.HasPrefix(got, `want: "Enter"`) || strings.Contains(got, "^") {
+		t.Errorf("no match:\n%s", got)
+	}
+}
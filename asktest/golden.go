@@ -0,0 +1,222 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Golden-file snapshots of the exact bytes rendered by the questions,
+ * ANSI colour codes included. Run the tests with -update to refresh
+ * the golden files.
+ *-----------------------------------------------------------------*/
+package asktest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goask"
+	"github.com/lordofscripts/goask/ask"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// directory of the golden files, relative to the package under test
+	GoldenDir = "testdata"
+	// extension of the golden files
+	GoldenExt = ".golden"
+)
+
+// the flag that rewrites the golden files (see RegisterUpdateFlag)
+const UpdateFlag = "update"
+
+// whether the golden files are rewritten with the actual output rather
+// than compared. A boolean -update flag of the test binary, whether
+// defined by the test package or by RegisterUpdateFlag, does the same.
+var Update bool = false
+
+var (
+	// an ANSI Select Graphic Rendition (colour) sequence
+	sgrPattern = regexp.MustCompile("\x1b\\[([0-9;]*)m")
+
+	// readable names of the colours, those of goask first
+	colourNames = map[string]string{
+		goask.ANSI_RED:    "red",
+		goask.ANSI_GREEN:  "green",
+		goask.ANSI_YELLOW: "yellow",
+		goask.ANSI_PURPLE: "purple",
+		goask.ANSI_BROWN:  "brown",
+		"\x1b[36m":        "cyan",
+		"\x1b[37m":        "white",
+		"\x1b[91m":        "bright-red",
+		"\x1b[92m":        "bright-green",
+		"\x1b[95m":        "bright-purple",
+		"\x1b[96m":        "bright-cyan",
+		"\x1b[97m":        "bright-white",
+	}
+)
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// answer a question with the input and get the exact bytes it rendered.
+// At end-of-input whatever is still asking takes its default. Not to be
+// used while a virtual console (see NewConsole) is active.
+func Render(question ask.ICurious, input string) string {
+	return RenderFunc(func() { question.Ask() }, input)
+}
+
+// run any interaction, e.g. a questionaire, with the input and get the
+// exact bytes it rendered (see Render).
+func RenderFunc(interaction func(), input string) string {
+	var out bytes.Buffer
+	restore := ask.CaptureConsole(strings.NewReader(input), &out)
+	defer restore()
+
+	interaction()
+	return out.String()
+}
+
+// define the -update flag (see Update) unless the test binary already
+// has a flag of that name. Call it from an init function or TestMain of
+// the test package:
+//
+//	func init() { asktest.RegisterUpdateFlag() }
+func RegisterUpdateFlag() {
+	if flag.Lookup(UpdateFlag) == nil {
+		flag.BoolVar(&Update, UpdateFlag, false, "rewrite the golden files with the actual output")
+	}
+}
+
+// render the ANSI colour codes in a readable form: a colour opens a tag
+// that is closed by the reset or by the next colour, for example
+// "\033[93m Pick one \033[0m" becomes "<yellow> Pick one </yellow>".
+// A reset with no colour to close is rendered as <reset> and any other
+// sequence by its code, e.g. <ansi 1;4>.
+func NormalizeANSI(s string) string {
+	var sb strings.Builder
+	open := ""
+	last := 0
+	for _, loc := range sgrPattern.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(s[last:loc[0]])
+		last = loc[1]
+
+		seq, code := s[loc[0]:loc[1]], s[loc[2]:loc[3]]
+		name, isColour := colourNames[seq]
+		switch {
+		case code == "0" || len(code) == 0:
+			if len(open) != 0 {
+				sb.WriteString("</" + open + ">")
+			} else {
+				sb.WriteString("<reset>")
+			}
+			open = ""
+		case isColour:
+			if len(open) != 0 {
+				sb.WriteString("</" + open + ">")
+			}
+			sb.WriteString("<" + name + ">")
+			open = name
+		default:
+			sb.WriteString("<ansi " + code + ">")
+		}
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+// compare the exact bytes with those of the golden file
+// testdata/<name>.golden, which is (re)written when running with -update.
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+	assertGolden(t, name, got)
+}
+
+// compare the output with the golden file as in AssertGolden, but with
+// the ANSI codes in their readable form (see NormalizeANSI).
+func AssertGoldenANSI(t testing.TB, name, got string) {
+	t.Helper()
+	assertGolden(t, name, NormalizeANSI(got))
+}
+
+// whether the golden files are being rewritten
+func updating() bool {
+	if Update {
+		return true
+	}
+	if f := flag.Lookup(UpdateFlag); f != nil {
+		if getter, ok := f.Value.(flag.Getter); ok {
+			on, _ := getter.Get().(bool)
+			return on
+		}
+	}
+	return false
+}
+
+// the path of a golden file
+func goldenPath(name string) string {
+	return filepath.Join(GoldenDir, name+GoldenExt)
+}
+
+// compare with the golden file or, with -update, rewrite it
+func assertGolden(t testing.TB, name, got string) {
+	t.Helper()
+	path := goldenPath(name)
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("asktest: updating %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("asktest: updating %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("asktest: %v (run the tests with -update to create it)", err)
+	}
+	if string(want) != got {
+		t.Errorf("asktest: output differs from %s (run the tests with -update to accept it)\n%s",
+			path, diffLines(string(want), got))
+	}
+}
+
+// a readable line by line comparison of the golden file and the output
+func diffLines(want, got string) string {
+	wantLines := strings.SplitAfter(want, "\n")
+	gotLines := strings.SplitAfter(got, "\n")
+	var sb strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		if i < len(wantLines) {
+			sb.WriteString(lineNote(i+1, "-", w))
+		}
+		if i < len(gotLines) {
+			sb.WriteString(lineNote(i+1, "+", g))
+		}
+	}
+	return sb.String()
+}
+
+// a line of the comparison, quoted so that the ANSI codes are visible
+func lineNote(number int, sign, line string) string {
+	return fmt.Sprintf("%s %4d: %q\n", sign, number, line)
+}
@@ -0,0 +1,82 @@
+package asktest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lordofscripts/goask"
+	"github.com/lordofscripts/goask/ask"
+)
+
+// the usual golden-file idiom must not clash with asktest
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func newPickOne() *ask.QuestionWithChoice {
+	return ask.NewMultipleChoiceQuestion("Pick one", []ask.InputSelection{
+		ask.NewInputSelection(0, "Tea"),
+		ask.NewInputSelection(1, "Coffee").WithHelp("Strong"),
+	})
+}
+
+func TestGoldenChoice(t *testing.T) {
+	out := Render(newPickOne(), "?\n1\n")
+	AssertGolden(t, "choice", out)
+	AssertGoldenANSI(t, "choice-ansi", out)
+}
+
+func TestGoldenInputRequest(t *testing.T) {
+	out := Render(ask.NewIntInputRequest("Enter integer", 7).WithHelp("Any number"), "x\n?\n42\n")
+	AssertGoldenANSI(t, "input-request", out)
+}
+
+func TestNormalizeANSI(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{goask.ANSI_YELLOW + " Pick one " + goask.ANSI_RESET, "<yellow> Pick one </yellow>"},
+		{goask.ANSI_YELLOW + "a" + goask.ANSI_GREEN + "b" + goask.ANSI_RESET, "<yellow>a</yellow><green>b</green>"},
+		{"plain" + goask.ANSI_RESET, "plain<reset>"},
+		{"\x1b[1;4mbold", "<ansi 1;4>bold"},
+		{goask.ANSI_PURPLE + "open", "<purple>open"},
+		{"no codes", "no codes"},
+	}
+	for _, c := range cases {
+		if got := NormalizeANSI(c.in); got != c.want {
+			t.Errorf("NormalizeANSI(%q): want %q got %q", c.in, c.want, got)
+		}
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	here, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(here)
+
+	Update = true
+	AssertGolden(t, "created", "first\n")
+	Update = false
+	AssertGolden(t, "created", "first\n")
+
+	got, err := os.ReadFile(filepath.Join(GoldenDir, "created"+GoldenExt))
+	if err != nil || string(got) != "first\n" {
+		t.Errorf("golden file: %q %v", got, err)
+	}
+	if want := "-    1: \"first\\n\"\n+    1: \"second\\n\"\n"; diffLines("first\n", "second\n") != want {
+		t.Errorf("diff:\n%s", diffLines("first\n", "second\n"))
+	}
+}
+
+func TestUpdateFlag(t *testing.T) {
+	// the -update flag defined above is honoured
+	if updating() != *update {
+		t.Errorf("updating: want %v", *update)
+	}
+	RegisterUpdateFlag() // already defined: no panic
+}
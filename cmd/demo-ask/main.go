@@ -55,7 +55,7 @@ import (
 func askPlainQuestions() {
 	fmt.Println("*** Plain Questions ***")
 
-	intInp := ask.NewIntInputRequest("Enter integer", 5)
+	intInp := ask.NewIntInputRequest("Enter integer", 5).WithHelp("Any whole number. Type ? for this help.")
 	intInp.Read()
 	fmt.Println("· Int input", intInp.Value)
 
@@ -73,9 +73,9 @@ func askMultipleChoice() {
 
 	question1 := ask.NewMultipleChoiceQuestion("Which one do you want?", []ask.InputSelection{
 		ask.NewInputSelection(0, "Default"),
-		ask.NewInputSelection(1, "One"),
-		ask.NewInputSelection(2, "Two"),
-	})
+		ask.NewInputSelection(1, "One").WithHelp("The first of many"),
+		ask.NewInputSelection(2, "Two").WithHelp("Second best"),
+	}).WithHelp("Pick an option by its number")
 	question1.Ask()
 	fmt.Println("· Question #1: ", question1.AsInt())
 
@@ -14,10 +14,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/askfsm"
 	"github.com/lordofscripts/goask/fsm"
 )
 
@@ -29,6 +33,8 @@ const (
 	TechSupport
 	ClaimsDept
 	Vacation
+	Help
+	Transferred
 	FinalState
 )
 
@@ -40,8 +46,6 @@ const (
 var _ fsm.OnEnterHandler = greeter
 var _ fsm.OnExitHandler = byer
 
-var myStateData MyUserData = MyUserData{Balance: 0.0, Taxes: 0.0, Fees: 0.0}
-
 // implements IUserData
 type MyUserData struct {
 	Balance float32
@@ -51,37 +55,30 @@ type MyUserData struct {
 
 func txVoice(msg string, args ...any) {
 	if len(args) == 0 {
-		fmt.Printf("%c %s\n", UNICODE_PHONE, msg)
+		fmt.Fprintf(ask.Output(), "%c %s\n", UNICODE_PHONE, msg)
 	} else {
-		fmt.Printf("%c ", UNICODE_PHONE)
-		fmt.Printf(msg, args...)
+		fmt.Fprintf(ask.Output(), "%c ", UNICODE_PHONE)
+		fmt.Fprintf(ask.Output(), msg, args...)
 	}
 }
 
 func txMusic(msg string) {
-	fmt.Printf("%c %s\n", UNICODE_MUSIC, msg)
+	fmt.Fprintf(ask.Output(), "%c %s\n", UNICODE_MUSIC, msg)
 }
 
 func greeter(sm fsm.IStateMachine) {
-	fmt.Println(rune(0x270b), " Hello!", sm.String())
+	fmt.Fprintln(ask.Output(), rune(0x270b), " Hello!", sm.String())
 }
 
 func byer(sm fsm.IStateMachine) {
-	fmt.Println(rune(0x270c), " Bye!", sm.String())
-}
-
-func getStateData(sm fsm.IStateMachine) *MyUserData {
-	if v, ok := sm.GetStateData().(*MyUserData); ok {
-		return v
-	}
-	panic("couldn't get state data!")
+	fmt.Fprintln(ask.Output(), rune(0x270c), " Bye!", sm.String())
 }
 
 func defineStates() *fsm.StateMachine[MyUserData] {
 	var sequencer *fsm.StateMachine[MyUserData]
 
 	st0 := fsm.NewState(InitialState, "S0", greeter, nil, false, func(sm fsm.IStateMachine) fsm.StateId {
-		fmt.Println(" * * * MAIN AUTOMATED RESPONSE MENU * * *")
+		fmt.Fprintln(ask.Output(), " * * * MAIN AUTOMATED RESPONSE MENU * * *")
 		q1 := ask.NewMultipleChoiceQuestion("What would you like to do?", []ask.InputSelection{
 			ask.NewInputSelection(0, "Hang up"),
 			ask.NewInputSelection(1, "Buy Data packages"),
@@ -93,19 +90,20 @@ func defineStates() *fsm.StateMachine[MyUserData] {
 		return nextStateId[q1.Ask().AsInt()]
 	})
 
-	calculateTaxAndFees := func(sm fsm.IStateMachine) {
-		stateData := getStateData(sm)
+	calculateTaxAndFees := func(stateData *MyUserData) {
 		if stateData.Balance > 0 {
 			stateData.Taxes = stateData.Balance * 0.14
 			stateData.Fees = 15.0
 		}
 	}
-	stX := fsm.NewState(FinalState, "SX", nil, byer, true, func(sm fsm.IStateMachine) fsm.StateId {
+	// typed states get compile-time checked access to the state data
+	stX := fsm.NewTypedState(FinalState, "SX", nil, func(sm *fsm.StateMachine[MyUserData], _ *MyUserData) {
+		byer(sm)
+	}, true, func(sm *fsm.StateMachine[MyUserData], data *MyUserData) fsm.StateId {
 		if sm.GetPrevious() == InitialState {
 			txVoice("Thank you for not bothering us more.\n")
 		}
-		calculateTaxAndFees(sm)
-		data := getStateData(sm)
+		calculateTaxAndFees(data)
 		if data.Balance > 0 {
 			txVoice("You spent:\n\tPurchases: $%s\n\tTaxes    : $%s\n\tFees     : $%s\n\tTotal    : $%s\n",
 				strconv.FormatFloat(float64(data.Balance), 'f', -1, 32),
@@ -114,17 +112,18 @@ func defineStates() *fsm.StateMachine[MyUserData] {
 				strconv.FormatFloat(float64(data.Balance)+float64(data.Taxes)+float64(data.Fees), 'f', -1, 32))
 		}
 		txVoice("Thank you for choosing us!")
-		fmt.Println("Hanging up...")
+		fmt.Fprintln(ask.Output(), "Hanging up...")
 		return FinalState
 	})
 
-	st1 := fsm.NewState(BuyData, "SD", nil, nil, false, func(sm fsm.IStateMachine) fsm.StateId {
+	st1 := fsm.NewTypedStateSimple(BuyData, "SD", false, func(sm *fsm.StateMachine[MyUserData], stateData *MyUserData) fsm.StateId {
 		q1 := ask.NewMultipleChoiceQuestion("Buy which DATA package?", []ask.InputSelection{
 			ask.NewInputSelection(0, "Cancel"),
 			ask.NewInputSelection(1, "3 days for $5"),
 			ask.NewInputSelection(2, "7 days for $6"),
 			ask.NewInputSelection(3, "10 days for $9"),
 			ask.NewInputSelection(4, "Want VOICE instead"),
+			ask.NewInputSelection(5, "Help"),
 		})
 		choice := q1.Ask().AsInt()
 		var nextState fsm.StateId
@@ -133,22 +132,24 @@ func defineStates() *fsm.StateMachine[MyUserData] {
 			nextState = InitialState
 		case 4:
 			nextState = BuyVoice
+		case 5:
+			nextState = Help
 		default:
 			costs := []float32{0, 5.0, 6.0, 9.0}
-			stateData := getStateData(sm)
 			stateData.Balance = costs[choice]
 			nextState = FinalState
 		}
 		return nextState
 	})
 
-	st2 := fsm.NewState(BuyVoice, "SV", nil, nil, false, func(sm fsm.IStateMachine) fsm.StateId {
+	st2 := fsm.NewTypedStateSimple(BuyVoice, "SV", false, func(sm *fsm.StateMachine[MyUserData], stateData *MyUserData) fsm.StateId {
 		q1 := ask.NewMultipleChoiceQuestion("Buy which VOICE package?", []ask.InputSelection{
 			ask.NewInputSelection(0, "Cancel"),
 			ask.NewInputSelection(1, "1 week for $7"),
 			ask.NewInputSelection(2, "2 weeks for $14"),
 			ask.NewInputSelection(3, "1 month for $35"),
 			ask.NewInputSelection(4, "Want DATA instead"),
+			ask.NewInputSelection(5, "Help"),
 		})
 		choice := q1.Ask().AsInt()
 		var nextState fsm.StateId
@@ -157,49 +158,29 @@ func defineStates() *fsm.StateMachine[MyUserData] {
 			nextState = InitialState
 		case 4:
 			nextState = BuyData
+		case 5:
+			nextState = Help
 		default:
 			costs := []float32{0, 7.0, 14.0, 35.0}
-			stateData := getStateData(sm)
 			stateData.Balance = costs[choice]
 			nextState = FinalState
 		}
 		return nextState
 	})
 
-	st3 := fsm.NewState(TechSupport, "ST", nil, nil, false, func(sm fsm.IStateMachine) fsm.StateId {
-		q1 := ask.NewMultipleChoiceQuestion("Select Technical Support area?", []ask.InputSelection{
-			ask.NewInputSelection(0, "Cancel"),
-			ask.NewInputSelection(1, "Internet"),
-			ask.NewInputSelection(2, "Phone"),
-			ask.NewInputSelection(3, "Cable TV"),
-		})
-		choice := q1.Ask().AsInt()
-		var nextState fsm.StateId
-		switch choice {
-		case 0:
-			nextState = InitialState
-		default:
-			nextState = Vacation
-		}
-		return nextState
+	// menus that merely choose the next state
+	st3 := askfsm.NewMenuState(TechSupport, "ST", "Select Technical Support area?", []askfsm.MenuOption{
+		{Text: "Cancel", Target: InitialState},
+		{Text: "Internet", Target: Vacation},
+		{Text: "Phone", Target: Vacation},
+		{Text: "Cable TV", Target: Vacation},
 	})
 
-	st4 := fsm.NewState(ClaimsDept, "SC", nil, nil, false, func(sm fsm.IStateMachine) fsm.StateId {
-		q1 := ask.NewMultipleChoiceQuestion("Which type of claim?", []ask.InputSelection{
-			ask.NewInputSelection(0, "Cancel"),
-			ask.NewInputSelection(1, "Sales returns"),
-			ask.NewInputSelection(2, "Customer service complaints"),
-			ask.NewInputSelection(3, "General feedback"),
-		})
-		choice := q1.Ask().AsInt()
-		var nextState fsm.StateId
-		switch choice {
-		case 0:
-			nextState = InitialState
-		default:
-			nextState = Vacation
-		}
-		return nextState
+	st4 := askfsm.NewMenuState(ClaimsDept, "SC", "Which type of claim?", []askfsm.MenuOption{
+		{Text: "Cancel", Target: InitialState},
+		{Text: "Sales returns", Target: Vacation},
+		{Text: "Customer service complaints", Target: Vacation},
+		{Text: "General feedback", Target: Vacation},
 	})
 
 	st5 := fsm.NewState(Vacation, "SF", func(im fsm.IStateMachine) {
@@ -208,15 +189,82 @@ func defineStates() *fsm.StateMachine[MyUserData] {
 		txVoice("We are transferring you to another department. Trust us!")
 	}, false, func(im fsm.IStateMachine) fsm.StateId {
 		txMusic("(Annoying music here)")
-		return fsm.StateFinal // use the default
+		<-im.Context().Done() // on hold until the time is up
+		return Vacation
+	})
+	// play the music again when the time is up, after 3 times transfer
+	st5.WithTimeout(3*time.Second, Vacation).MaxTimeouts(3, Transferred)
+	// declaring the transitions lets the FSM validate the graph before
+	// it starts and reject undeclared transitions while it runs. The
+	// labels are used when exporting the diagram.
+	st0.AllowTransition(FinalState, "Hang up").
+		AllowTransition(BuyData, "Buy Data packages").
+		AllowTransition(BuyVoice, "Buy Voice packages").
+		AllowTransition(TechSupport, "Technical Support").
+		AllowTransition(ClaimsDept, "Claims Department")
+	st1.AllowTransition(InitialState, "Cancel").
+		AllowTransition(BuyVoice, "Want VOICE instead").
+		AllowTransition(Help, "Help").
+		AllowTransition(FinalState, "Buy package")
+	st2.AllowTransition(InitialState, "Cancel").
+		AllowTransition(BuyData, "Want DATA instead").
+		AllowTransition(Help, "Help").
+		AllowTransition(FinalState, "Buy package")
+	st5.AllowTransition(Transferred, "Transfer")
+
+	// only check out when something was bought, else back to the menu
+	hasBalance := fsm.NewTypedGuard(func(_ *fsm.StateMachine[MyUserData], data *MyUserData) bool {
+		return data.Balance > 0
+	})
+	st1.When(FinalState, "balance > 0", 0, hasBalance).Otherwise(InitialState)
+	st2.When(FinalState, "balance > 0", 0, hasBalance).Otherwise(InitialState)
+
+	// help returns to whichever menu invoked it
+	stH := fsm.NewStateSimple(Help, "SH", false, func(sm fsm.IStateMachine) fsm.StateId {
+		txVoice("Packages are valid from the moment of purchase. Taxes and fees apply.")
+		return fsm.StateHistory
+	}).AllowTransition(fsm.StateHistory, "Back")
+
+	// the rude final state: transferred to nowhere
+	stT := fsm.NewStateSimple(Transferred, "STX", true, func(sm fsm.IStateMachine) fsm.StateId {
+		txVoice("All our agents are busy. Goodbye.")
+		return Transferred
 	})
-	// we have two final states, a nice one and a rude one
-	sequencer = fsm.NewStateMachine[MyUserData]("Customer Service", st0, st1, st2, st3, st4, st5, stX, fsm.DefaultFinalState).SetUserDataObject(&myStateData)
+
+	// we have two final states, a nice one and a rude one. Every machine
+	// gets its own state data so that each call starts afresh.
+	sequencer = fsm.NewStateMachine[MyUserData]("Customer Service", st0, st1, st2, st3, st4, st5, stH, stX, stT).SetUserDataObject(&MyUserData{})
 
 	return sequencer
 }
 
 func main() {
+	var exportDot, exportMermaid, trace bool
+	flag.BoolVar(&exportDot, "dot", false, "print the IVR flow as a Graphviz DOT diagram and exit")
+	flag.BoolVar(&exportMermaid, "mermaid", false, "print the IVR flow as a Mermaid diagram and exit")
+	flag.BoolVar(&trace, "trace", false, "stream the transitions as JSON lines to stderr")
+	flag.Parse()
+
 	sm := defineStates()
-	sm.Start()
+	if exportDot || exportMermaid {
+		var err error
+		if exportDot {
+			err = sm.ExportDOT(os.Stdout)
+		} else {
+			err = sm.ExportMermaid(os.Stdout)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if trace {
+		sm.SetTracer(fsm.NewJSONTracer(os.Stderr))
+	}
+	if err := sm.Start(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 }
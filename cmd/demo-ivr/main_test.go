@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goask/fsmtest"
+)
+
+func TestBuyData(t *testing.T) {
+	runner := fsmtest.NewRunner(defineStates)
+	session := runner.Run("1", "2")
+	session.AssertNoError(t)
+	session.AssertPath(t, InitialState, BuyData, FinalState)
+	session.AssertOutput(t, "Purchases: $6")
+	t.Log(runner.Coverage())
+}
+
+func TestRunsStartAfresh(t *testing.T) {
+	runner := fsmtest.NewRunner(defineStates)
+	runner.Run("1", "2").AssertNoError(t)
+
+	session := runner.Run("0")
+	session.AssertNoError(t)
+	session.AssertPath(t, InitialState, FinalState)
+	session.AssertData(t, MyUserData{})
+	if strings.Contains(session.Output, "You spent") {
+		t.Errorf("the previous purchase is shown:\n%s", session.Output)
+	}
+}
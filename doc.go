@@ -32,4 +32,5 @@ const (
 	ICON_HIGH_VOLTAGE rune = rune(0x26a1)  // ⚡
 	ICON_CROSSLANE    rune = rune(0x26cc)  // ⛌
 	ICON_NO_ENTRY     rune = rune(0x26d4)  // ⛔
+	ICON_INFORMATION  rune = rune(0x2139)  // ℹ
 )
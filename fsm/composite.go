@@ -0,0 +1,167 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Hierarchical (nested) states. A composite state contains its own
+ * child StateMachine that is run every time the state is entered.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"fmt"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *				I n t e r f a c e s
+ *-----------------------------------------------------------------*/
+
+var _ ISubMachine = (*StateMachine[any])(nil)
+
+// Interface of a state machine that can be nested in a composite state.
+// It is implemented by StateMachine[T] of any data type T.
+type ISubMachine interface {
+	IStateMachine
+	// run the machine nested in a state of the outer machine, resuming
+	// the last active state if resume is true (shallow history)
+	runNested(outer IStateMachine, resume bool) (next StateId, bubbled bool, err error)
+	// validate the machine nested in a machine that knows the given states
+	validateNested(outer func(StateId) bool) error
+	// whether the state is known by this machine or its outer machines
+	knows(id StateId) bool
+	// begin running the machine as a region of a parallel state
+	beginRegion(outer IStateMachine)
+	// execute one state of a region, returning whether it is done
+	stepRegion() (bool, error)
+	// stop running the machine as a region
+	endRegion()
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) Creates a composite state containing a child state machine.
+// Every time the state is run, after its OnEnter, the child machine
+// starts at its initial (default) state (see also WithHistory):
+//   - a child state that transitions to a state the child machine does
+//     not have, but an outer machine does, bubbles the transition up:
+//     the OnExit of the child state runs, then that of the composite.
+//   - when the child reaches a terminal state, the body (if not nil)
+//     decides the next state, else the guards (see When), else the
+//     state returned by the child's terminal state is used if it is
+//     a state of the outer machine.
+//
+// If the child machine has no state data of its own, it shares that of
+// the outer machine when both have the same data type.
+func NewCompositeState(id StateId, name string, onEnter OnEnterHandler, onExit OnExitHandler, child ISubMachine, body StateMainHandler) *State {
+	state := NewState(id, name, onEnter, onExit, false, body)
+	state.nested = child
+	return state
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// get the outer machine when this machine is nested in a composite
+// state (see NewCompositeState), else nil.
+func (sm *StateMachine[T]) GetParent() IStateMachine {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.outer
+}
+
+// make a composite state remember its last active child state (shallow
+// history): when it is entered again after a transition bubbled out of
+// it, the child machine resumes that state rather than its initial one.
+func (s *State) WithHistory() *State {
+	s.history = true
+	return s
+}
+
+// get the child state machine of a composite state, else nil.
+func (s *State) Child() ISubMachine {
+	return s.nested
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements ISubMachine
+func (sm *StateMachine[T]) runNested(outer IStateMachine, resume bool) (StateId, bool, error) {
+	start := sm.enterNested(outer, resume)
+	defer func() { sm.setStatus(false, sm.IsDone()) }()
+
+	sm.notifyStart()
+	return sm.loop(start, sm.outerKnows)
+}
+
+// prepare to run the machine nested in a state of the outer machine and
+// return the state to start at.
+func (sm *StateMachine[T]) enterNested(outer IStateMachine, resume bool) *State {
+	outerKnows := func(StateId) bool { return false }
+	if parent, ok := outer.(ISubMachine); ok {
+		outerKnows = parent.knows
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	start := sm.initialState
+	if resume && sm.current != nil && !sm.isFinished {
+		start = sm.current
+	}
+	if sm.stateData == nil {
+		if data, ok := outer.GetStateData().(*T); ok {
+			sm.stateData = data
+		}
+	}
+	sm.outer = outer
+	sm.outerKnows = outerKnows
+	sm.ctx = outer.Context()
+	sm.isActive = true
+	sm.isFinished = false
+	sm.previousState = StateNone
+	sm.since = time.Now()
+	return start
+}
+
+// implements ISubMachine
+func (sm *StateMachine[T]) validateNested(outer func(StateId) bool) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.validate(outer)
+}
+
+// implements ISubMachine
+func (sm *StateMachine[T]) knows(id StateId) bool {
+	return sm.knowsWithin(sm.outerKnows)(id)
+}
+
+// run the child machine of a composite state and decide the next state
+func (s *State) runNested() (StateId, error) {
+	next, bubbled, err := s.nested.runNested(s.parent, s.history)
+	if err != nil {
+		return next, fmt.Errorf("%s: %w", s.describe(), err)
+	}
+	if bubbled {
+		return next, nil
+	}
+
+	// the child machine reached a terminal state
+	if s.body != nil {
+		return s.body(s.parent), nil
+	}
+	if s.hasGuards() {
+		return StateNone, nil
+	}
+	if parent, ok := s.parent.(ISubMachine); ok && next != s.Id && parent.knows(next) {
+		return next, nil
+	}
+	return next, fmt.Errorf("%s: child machine finished: %w", s.describe(), ErrNoTransition)
+}
@@ -0,0 +1,71 @@
+package fsm
+
+import (
+	"testing"
+)
+
+const (
+	stChildA StateId = iota + 10
+	stChildB
+	stChildDone
+)
+
+func TestCompositeBubblesAndResumes(t *testing.T) {
+	r := &recorder{}
+	visits := 0
+	child := NewStateMachine[int]("child",
+		r.state(stChildA, "A", false, stChildB),
+		NewState(stChildB, "B",
+			func(IStateMachine) { r.record("enter B") },
+			func(IStateMachine) { r.record("exit B") },
+			false,
+			func(IStateMachine) StateId {
+				// leave for the outer middle state on the first visit
+				if visits++; visits == 1 {
+					return stMiddle
+				}
+				return stChildDone
+			}),
+		NewStateSimple(stChildDone, "Done", true, func(IStateMachine) StateId { return stEnd }))
+	composite := NewCompositeState(stStart, "Composite",
+		func(IStateMachine) { r.record("enter Composite") },
+		func(IStateMachine) { r.record("exit Composite") },
+		child, nil).WithHistory()
+	middle := NewStateSimple(stMiddle, "Middle", false, func(IStateMachine) StateId { return stStart })
+	sm := NewStateMachine[int]("outer", composite, middle, NewStateSimple(stEnd, "End", true, nil))
+
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != stEnd {
+		t.Errorf("current: want %d got %d", stEnd, got)
+	}
+	assertCalls(t, []string{
+		"enter Composite", "enter A", "body A", "exit A", "enter B", "exit B", "exit Composite",
+		// resumed at B (shallow history) rather than A
+		"enter Composite", "enter B", "exit B", "exit Composite",
+	}, r.get())
+	if child.GetParent() != sm {
+		t.Error("the child machine does not know its parent")
+	}
+}
+
+func TestCompositeSharesData(t *testing.T) {
+	child := NewStateMachine[int]("child",
+		NewStateSimple(stChildA, "A", false, func(im IStateMachine) StateId {
+			*im.GetStateData().(*int) = 7
+			return stChildDone
+		}),
+		NewStateSimple(stChildDone, "Done", true, func(IStateMachine) StateId { return stEnd }))
+	sm := NewStateMachine[int]("outer",
+		NewCompositeState(stStart, "Composite", nil, nil, child, nil),
+		NewStateSimple(stEnd, "End", true, nil))
+	sm.SetUserDataObject(new(int))
+
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if got := *sm.Data(); got != 7 {
+		t.Errorf("data: want 7 got %d", got)
+	}
+}
@@ -0,0 +1,351 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Declarative state machines: the states, transitions and the names
+ * of the handlers to bind are described in a JSON document and the
+ * handlers are looked up in a registry.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	ErrInvalidDefinition = errors.New("invalid state machine definition")
+	ErrUnknownHandler    = errors.New("handler not registered")
+	ErrUnknownKind       = errors.New("state kind not registered")
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// Builds a state of a registered kind (see Registry.RegisterKind) from
+// its definition, whose Params are particular to the kind. The declared
+// and guarded transitions and the timeout of the definition are then
+// added to the state that is returned.
+type StateBuilder func(def StateDefinition, registry *Registry) (*State, error)
+
+// A registry of named handlers that a JSON definition binds to its
+// states and transitions (see NewStateMachineFromJSON).
+type Registry struct {
+	enter   map[string]OnEnterHandler
+	exit    map[string]OnExitHandler
+	bodies  map[string]StateMainHandler
+	guards  map[string]GuardFunc
+	actions map[string]ActionFunc
+	kinds   map[string]StateBuilder
+}
+
+// The JSON definition of a state machine, for example:
+//
+//	{
+//	  "name": "Customer Service",
+//	  "initial": 1,
+//	  "states": [
+//	    { "id": 1, "name": "Menu", "onEnter": "greet", "body": "menu",
+//	      "transitions": [ { "to": 2, "label": "Hang up" } ] },
+//	    { "id": 2, "name": "Bye", "terminal": true, "body": "bye" }
+//	  ]
+//	}
+type Definition struct {
+	Name       string            `json:"name"`
+	Initial    StateId           `json:"initial,omitempty"` // default: the first state
+	ErrorState StateId           `json:"errorState,omitempty"`
+	States     []StateDefinition `json:"states"`
+	Events     []EventDefinition `json:"events,omitempty"`
+}
+
+// The definition of a state. Handlers are given by their registered
+// name; an empty name means no handler. A state of a registered kind,
+// for example an askfsm menu, is built by the StateBuilder of the kind
+// from its Params instead of from its handlers.
+type StateDefinition struct {
+	Id          StateId                `json:"id"`
+	Name        string                 `json:"name"`
+	Kind        string                 `json:"kind,omitempty"`
+	Params      json.RawMessage        `json:"params,omitempty"` // of the kind
+	Terminal    bool                   `json:"terminal,omitempty"`
+	OnEnter     string                 `json:"onEnter,omitempty"`
+	OnExit      string                 `json:"onExit,omitempty"`
+	Body        string                 `json:"body,omitempty"`
+	Transitions []TransitionDefinition `json:"transitions,omitempty"`
+	Guards      []GuardDefinition      `json:"guards,omitempty"`
+	Otherwise   StateId                `json:"otherwise,omitempty"`
+	Timeout     *TimeoutDefinition     `json:"timeout,omitempty"`
+}
+
+// A declared transition (see State.AllowTransition)
+type TransitionDefinition struct {
+	To    StateId `json:"to"`
+	Label string  `json:"label,omitempty"`
+}
+
+// A guarded transition (see State.When)
+type GuardDefinition struct {
+	To       StateId `json:"to"`
+	Name     string  `json:"name"`
+	Priority int     `json:"priority,omitempty"`
+	Guard    string  `json:"guard"`
+}
+
+// The time limit of a state (see State.WithTimeout and MaxTimeouts)
+type TimeoutDefinition struct {
+	After      string  `json:"after"` // e.g. "10s"
+	To         StateId `json:"to"`
+	Max        int     `json:"max,omitempty"`
+	Escalation StateId `json:"escalation,omitempty"`
+}
+
+// A transition of the event-driven mode (see Transition)
+type EventDefinition struct {
+	From      StateId `json:"from"`
+	Event     Event   `json:"event"`
+	To        StateId `json:"to"`
+	Guard     string  `json:"guard,omitempty"`
+	GuardName string  `json:"guardName,omitempty"`
+	Priority  int     `json:"priority,omitempty"`
+	Action    string  `json:"action,omitempty"`
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) an empty handler registry
+func NewRegistry() *Registry {
+	return &Registry{
+		enter:   make(map[string]OnEnterHandler),
+		exit:    make(map[string]OnExitHandler),
+		bodies:  make(map[string]StateMainHandler),
+		guards:  make(map[string]GuardFunc),
+		actions: make(map[string]ActionFunc),
+		kinds:   make(map[string]StateBuilder),
+	}
+}
+
+// (ctor) builds a state machine from its JSON definition (see
+// Definition), binding the handlers by name from the registry. Unknown
+// fields and handlers that are not registered are reported as errors.
+// The machine is not validated; Start() does that.
+func NewStateMachineFromJSON[T any](r io.Reader, registry *Registry) (*StateMachine[T], error) {
+	var def Definition
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDefinition, err)
+	}
+	return NewStateMachineFromDefinition[T](def, registry)
+}
+
+// (ctor) builds a state machine from a definition, binding the handlers
+// by name from the registry (see NewStateMachineFromJSON).
+func NewStateMachineFromDefinition[T any](def Definition, registry *Registry) (*StateMachine[T], error) {
+	if len(def.States) == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDefinition, ErrNoStates)
+	}
+
+	initialId := def.Initial
+	if initialId == StateNone {
+		initialId = def.States[0].Id
+	}
+
+	problems := make([]error, 0)
+	var initial *State = nil
+	others := make([]*State, 0, len(def.States))
+	for _, sd := range def.States {
+		state, err := registry.buildState(sd)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if sd.Id == initialId && initial == nil {
+			initial = state
+		} else {
+			others = append(others, state)
+		}
+	}
+	if initial == nil {
+		problems = append(problems, fmt.Errorf("initial state %d: %w", initialId, ErrMissingTarget))
+	}
+
+	sm := NewStateMachine[T](def.Name, initial, others...)
+	if def.ErrorState != StateNone {
+		sm.SetErrorState(def.ErrorState)
+	}
+	for _, ed := range def.Events {
+		t, err := registry.buildEvent(ed)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		sm.AddTransition(t)
+	}
+
+	if len(problems) != 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDefinition, errors.Join(problems...))
+	}
+	return sm, nil
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// register an OnEnter handler by name
+func (r *Registry) RegisterEnter(name string, handler OnEnterHandler) *Registry {
+	r.enter[name] = handler
+	return r
+}
+
+// register an OnExit handler by name
+func (r *Registry) RegisterExit(name string, handler OnExitHandler) *Registry {
+	r.exit[name] = handler
+	return r
+}
+
+// register a state body by name
+func (r *Registry) RegisterBody(name string, handler StateMainHandler) *Registry {
+	r.bodies[name] = handler
+	return r
+}
+
+// register a guard condition by name
+func (r *Registry) RegisterGuard(name string, guard GuardFunc) *Registry {
+	r.guards[name] = guard
+	return r
+}
+
+// register a transition action by name
+func (r *Registry) RegisterAction(name string, action ActionFunc) *Registry {
+	r.actions[name] = action
+	return r
+}
+
+// register the builder of a kind of state, e.g. "menu" (see
+// askfsm.RegisterMenuKind)
+func (r *Registry) RegisterKind(kind string, builder StateBuilder) *Registry {
+	r.kinds[kind] = builder
+	return r
+}
+
+// get a registered action by name. An empty name means no action.
+// Used by the builders of the kinds of states.
+func (r *Registry) Action(name string) (ActionFunc, error) {
+	return lookup(r.actions, "action", name)
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// build a state from its definition
+func (r *Registry) buildState(sd StateDefinition) (*State, error) {
+	state, err := r.newState(sd)
+	if err != nil {
+		return nil, fmt.Errorf("state %d (%s): %w", sd.Id, sd.Name, err)
+	}
+
+	problems := make([]error, 0)
+	for _, td := range sd.Transitions {
+		if len(td.Label) != 0 {
+			state.AllowTransition(td.To, td.Label)
+		} else {
+			state.AllowTransitions(td.To)
+		}
+	}
+	for _, gd := range sd.Guards {
+		guard, err := lookup(r.guards, "guard", gd.Guard)
+		problems = append(problems, err)
+		state.When(gd.To, gd.Name, gd.Priority, guard)
+	}
+	if sd.Otherwise != StateNone {
+		state.Otherwise(sd.Otherwise)
+	}
+	if sd.Timeout != nil {
+		limit, err := time.ParseDuration(sd.Timeout.After)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("timeout: %w", err))
+		} else {
+			state.WithTimeout(limit, sd.Timeout.To)
+		}
+		if sd.Timeout.Max > 0 {
+			state.MaxTimeouts(sd.Timeout.Max, sd.Timeout.Escalation)
+		}
+	}
+
+	if err := errors.Join(problems...); err != nil {
+		return nil, fmt.Errorf("%s: %w", state.describe(), err)
+	}
+	return state, nil
+}
+
+// create the state of a definition, with its handlers or with the
+// builder of its kind
+func (r *Registry) newState(sd StateDefinition) (*State, error) {
+	if len(sd.Kind) == 0 {
+		enter, enterErr := lookup(r.enter, "onEnter", sd.OnEnter)
+		exit, exitErr := lookup(r.exit, "onExit", sd.OnExit)
+		body, bodyErr := lookup(r.bodies, "body", sd.Body)
+		if err := errors.Join(enterErr, exitErr, bodyErr); err != nil {
+			return nil, err
+		}
+		return NewState(sd.Id, sd.Name, enter, exit, sd.Terminal, body), nil
+	}
+
+	builder, ok := r.kinds[sd.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, sd.Kind)
+	}
+	if len(sd.OnEnter) != 0 || len(sd.OnExit) != 0 || len(sd.Body) != 0 {
+		return nil, fmt.Errorf("a state of kind %q has no onEnter, onExit nor body", sd.Kind)
+	}
+	return builder(sd, r)
+}
+
+// build an event-driven transition from its definition
+func (r *Registry) buildEvent(ed EventDefinition) (Transition, error) {
+	guard, guardErr := lookup(r.guards, "guard", ed.Guard)
+	action, actionErr := lookup(r.actions, "action", ed.Action)
+	t := Transition{
+		From:      ed.From,
+		Event:     ed.Event,
+		To:        ed.To,
+		Guard:     guard,
+		GuardName: ed.GuardName,
+		Priority:  ed.Priority,
+		Action:    action,
+	}
+	if err := errors.Join(guardErr, actionErr); err != nil {
+		return t, fmt.Errorf("event %q from state %d: %w", ed.Event, ed.From, err)
+	}
+	return t, nil
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// look up a handler by name. An empty name means no handler.
+func lookup[H any](handlers map[string]H, kind, name string) (H, error) {
+	var none H
+	if len(name) == 0 {
+		return none, nil
+	}
+	handler, ok := handlers[name]
+	if !ok {
+		return none, fmt.Errorf("%w: %s %q", ErrUnknownHandler, kind, name)
+	}
+	return handler, nil
+}
@@ -0,0 +1,126 @@
+package fsm
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const definitionJSON = `{
+  "name": "Order",
+  "states": [
+    { "id": 1, "name": "Cart", "onEnter": "count",
+      "guards": [ { "to": 2, "name": "paid", "guard": "paid" } ],
+      "otherwise": 3 },
+    { "id": 2, "name": "Shipped", "terminal": true },
+    { "id": 3, "name": "Cancelled", "terminal": true }
+  ]
+}`
+
+func newOrderRegistry(entered *int) *Registry {
+	return NewRegistry().
+		RegisterEnter("count", func(IStateMachine) { *entered++ }).
+		RegisterGuard("paid", func(_ IStateMachine, payload any) bool { return *payload.(*bool) })
+}
+
+func TestMachineFromJSON(t *testing.T) {
+	for _, paid := range []bool{true, false} {
+		entered := 0
+		sm, err := NewStateMachineFromJSON[bool](strings.NewReader(definitionJSON), newOrderRegistry(&entered))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sm.SetUserDataObject(&paid)
+		if err := sm.Start(); err != nil {
+			t.Fatal(err)
+		}
+		want := StateId(3)
+		if paid {
+			want = 2
+		}
+		if got := sm.Current(); got != want || entered != 1 {
+			t.Errorf("paid %t: in %d, entered %d", paid, got, entered)
+		}
+	}
+}
+
+func TestMachineFromJSONUnknownHandler(t *testing.T) {
+	_, err := NewStateMachineFromJSON[bool](strings.NewReader(definitionJSON), NewRegistry())
+	if !errors.Is(err, ErrInvalidDefinition) || !errors.Is(err, ErrUnknownHandler) {
+		t.Fatalf("want ErrUnknownHandler got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"count"`) {
+		t.Errorf("the missing handler is not named: %v", err)
+	}
+}
+
+func TestMachineFromJSONEvents(t *testing.T) {
+	const evented = `{
+  "name": "Door",
+  "states": [
+    { "id": 1, "name": "Closed" },
+    { "id": 2, "name": "Open" },
+    { "id": 3, "name": "Gone", "terminal": true }
+  ],
+  "events": [
+    { "from": 1, "event": "open", "to": 2, "guard": "unlocked", "guardName": "unlocked" },
+    { "from": 2, "event": "leave", "to": 3, "action": "wave" }
+  ]
+}`
+	waved := false
+	registry := NewRegistry().
+		RegisterGuard("unlocked", func(_ IStateMachine, payload any) bool { return payload == "key" }).
+		RegisterAction("wave", func(IStateMachine, any) { waved = true })
+	sm, err := NewStateMachineFromJSON[int](strings.NewReader(evented), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Fire("open", nil); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("want ErrInvalidEvent got %v", err)
+	}
+	for _, ev := range []Event{"open", "leave"} {
+		if err := sm.Fire(ev, "key"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !sm.IsDone() || !waved {
+		t.Errorf("done %t, waved %t", sm.IsDone(), waved)
+	}
+}
+
+func TestMachineFromJSONKinds(t *testing.T) {
+	const kinded = `{
+  "name": "Kinds",
+  "states": [
+    { "id": 1, "name": "Jump", "kind": "jump", "params": { "to": 2 } },
+    { "id": 2, "name": "End", "terminal": true }
+  ]
+}`
+	if _, err := NewStateMachineFromJSON[int](strings.NewReader(kinded), NewRegistry()); !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("want ErrUnknownKind got %v", err)
+	}
+
+	registry := NewRegistry().RegisterKind("jump", func(def StateDefinition, _ *Registry) (*State, error) {
+		var params struct {
+			To StateId `json:"to"`
+		}
+		if err := json.Unmarshal(def.Params, &params); err != nil {
+			return nil, err
+		}
+		return NewStateSimple(def.Id, def.Name, def.Terminal, func(IStateMachine) StateId { return params.To }), nil
+	})
+	sm, err := NewStateMachineFromJSON[int](strings.NewReader(kinded), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != 2 {
+		t.Errorf("current: want 2 got %d", got)
+	}
+}
@@ -0,0 +1,193 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Event-driven mode of the Finite State Machine. Rather than letting
+ * each state body decide its successor, transitions are defined as
+ * (from, event) -> to and events are fired from any goroutine.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	ErrInvalidEvent = errors.New("event not valid in the current state")
+	ErrNotActive    = errors.New("state machine is not active")
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// An event that triggers a transition in event-driven mode
+type Event string
+
+// A guard condition of a transition. The transition is only taken if
+// the guard returns true. The payload is the one given to Fire().
+type GuardFunc func(sm IStateMachine, payload any) bool
+
+// An action executed while a transition is taken, after the OnExit of
+// the source state and before the OnEnter of the target state.
+type ActionFunc func(sm IStateMachine, payload any)
+
+// A transition of the event-driven mode: when Event is fired while the
+// machine is in state From, and the (optional) Guard allows it, the
+// machine executes the (optional) Action and transitions to To. The
+// guarded transitions of the same state and event are evaluated from
+// the highest to the lowest Priority; an unguarded one is the default.
+type Transition struct {
+	From      StateId
+	Event     Event
+	To        StateId
+	Guard     GuardFunc
+	GuardName string // shown in diagrams and traces
+	Priority  int    // guards with higher priority are evaluated first
+	Action    ActionFunc
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// add an event-driven transition. Several transitions may be defined
+// for the same state and event; the guarded ones are evaluated by
+// priority (then in the order they were added) and the first whose
+// guard allows it is taken, else the first unguarded one (the default).
+func (sm *StateMachine[T]) AddTransition(t Transition) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.events = append(sm.events, t)
+	return sm
+}
+
+// get the event-driven transitions defined for the machine (see AddTransition)
+func (sm *StateMachine[T]) EventTransitions() []Transition {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return append([]Transition(nil), sm.events...)
+}
+
+// begin running the machine in event-driven mode. The machine is
+// validated and the initial state entered (its OnEnter is executed),
+// or the current state if restored from a snapshot (see Restore).
+// From then on the machine only moves when Fire() is called. State
+// bodies are not executed in this mode.
+func (sm *StateMachine[T]) Begin() error {
+	if err := sm.IsValid(); err != nil {
+		return sm.notifyError(fmt.Errorf("state machine %q is invalid: %w", sm.name, err))
+	}
+
+	sm.fireMu.Lock()
+	defer sm.fireMu.Unlock()
+
+	start := sm.resumeState()
+	sm.mu.Lock()
+	sm.isActive = true
+	sm.isFinished = false
+	sm.current = start
+	sm.since = time.Now()
+	sm.mu.Unlock()
+
+	sm.notifyStart()
+	start.enter()
+	return nil
+}
+
+// fire an event with an optional payload. It can be called from any
+// goroutine, but not from within the handlers, guards or actions of
+// the same machine. An error wrapping ErrInvalidEvent is returned if
+// no transition of the current state accepts the event.
+func (sm *StateMachine[T]) Fire(event Event, payload any) error {
+	sm.fireMu.Lock()
+	defer sm.fireMu.Unlock()
+
+	sm.mu.Lock()
+	active, from := sm.isActive, sm.current
+	candidates := make([]Transition, 0)
+	if from != nil {
+		for _, t := range sm.events {
+			if t.From == from.Id && t.Event == event {
+				candidates = append(candidates, t)
+			}
+		}
+	}
+	sm.mu.Unlock()
+	sortCandidates(candidates)
+
+	if !active || from == nil {
+		return sm.notifyError(fmt.Errorf("state machine %q: event %q: %w", sm.name, event, ErrNotActive))
+	}
+
+	for _, t := range candidates {
+		if t.Guard != nil && !t.Guard(sm, payload) {
+			continue
+		}
+		return sm.notifyError(sm.takeTransition(from, t, payload))
+	}
+
+	return sm.notifyError(fmt.Errorf("state machine %q: event %q in %s: %w", sm.name, event, from.describe(), ErrInvalidEvent))
+}
+
+// get the id of the current state, or StateNone if the machine has
+// not started yet. In between steps (see Step) it is the state to be
+// executed next.
+func (sm *StateMachine[T]) Current() StateId {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.current == nil {
+		return StateNone
+	}
+	return sm.current.Id
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// execute the transition from the current state. A transition to self
+// only executes the action.
+func (sm *StateMachine[T]) takeTransition(from *State, t Transition, payload any) error {
+	sm.mu.Lock()
+	to, ok := sm.states[t.To]
+	sm.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("state machine %q: %s transitioned to state %d: %w", sm.name, from.describe(), t.To, ErrUnknownState)
+	}
+
+	if to != from {
+		from.exit()
+	}
+	if t.Action != nil {
+		t.Action(sm, payload)
+	}
+	sm.SetCause(eventLabel(t))
+	sm.observe(from.Id, to.Id)
+	if to == from {
+		return nil
+	}
+
+	sm.mu.Lock()
+	sm.previousState = from.Id
+	sm.current = to
+	sm.mu.Unlock()
+
+	to.enter()
+
+	if to.isTerminal {
+		sm.setStatus(false, true)
+		sm.notifyFinish()
+	}
+	return nil
+}
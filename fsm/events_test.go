@@ -0,0 +1,120 @@
+package fsm
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+const (
+	evGo   Event = "go"
+	evTick Event = "tick"
+)
+
+func newEventMachine() *StateMachine[int] {
+	return NewStateMachine[int]("events",
+		NewStateSimple(stStart, "Start", false, nil),
+		NewStateSimple(stMiddle, "Middle", false, nil),
+		NewStateSimple(stEnd, "End", true, nil))
+}
+
+func TestFireBeforeBegin(t *testing.T) {
+	sm := newEventMachine()
+	sm.AddTransition(Transition{From: stStart, Event: evGo, To: stEnd})
+	if err := sm.Fire(evGo, nil); !errors.Is(err, ErrNotActive) {
+		t.Fatalf("want ErrNotActive got %v", err)
+	}
+}
+
+func TestFireGuardsAndActions(t *testing.T) {
+	sm := newEventMachine()
+	var paid any
+	sm.AddTransition(Transition{From: stStart, Event: evGo, To: stEnd,
+		Guard: func(_ IStateMachine, payload any) bool { return payload == "vip" }, GuardName: "vip", Priority: 1})
+	sm.AddTransition(Transition{From: stStart, Event: evGo, To: stMiddle,
+		Action: func(_ IStateMachine, payload any) { paid = payload }})
+	sm.AddTransition(Transition{From: stMiddle, Event: evGo, To: stEnd})
+	if err := sm.Begin(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sm.Fire(evTick, nil); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("want ErrInvalidEvent got %v", err)
+	}
+	if err := sm.Fire(evGo, "regular"); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Current() != stMiddle || paid != "regular" {
+		t.Errorf("default transition: in %d with payload %v", sm.Current(), paid)
+	}
+	if err := sm.Fire(evGo, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !sm.IsDone() || sm.Current() != stEnd {
+		t.Errorf("status: %s in %d", sm, sm.Current())
+	}
+	if err := sm.Fire(evGo, nil); !errors.Is(err, ErrNotActive) {
+		t.Errorf("finished machine: want ErrNotActive got %v", err)
+	}
+}
+
+func TestFireGuardPriority(t *testing.T) {
+	sm := newEventMachine()
+	sm.AddTransition(Transition{From: stStart, Event: evGo, To: stMiddle,
+		Guard: func(IStateMachine, any) bool { return true }, Priority: 1})
+	sm.AddTransition(Transition{From: stStart, Event: evGo, To: stEnd,
+		Guard: func(IStateMachine, any) bool { return true }, Priority: 2})
+	if err := sm.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Fire(evGo, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != stEnd {
+		t.Errorf("current: want %d got %d", stEnd, got)
+	}
+}
+
+func TestFireConcurrently(t *testing.T) {
+	const firers, fires = 8, 50
+	ticks := 0
+	sm := NewStateMachine[int]("ticks",
+		NewStateSimple(stStart, "Start", false, nil),
+		NewStateSimple(stEnd, "End", true, nil))
+	sm.AddTransition(Transition{From: stStart, Event: evTick, To: stStart,
+		Action: func(IStateMachine, any) { ticks++ }})
+	sm.AddTransition(Transition{From: stStart, Event: evGo, To: stEnd})
+	if err := sm.Begin(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < firers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < fires; j++ {
+				if err := sm.Fire(evTick, j); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < fires; j++ {
+				_ = sm.Current()
+				_ = sm.IsActive()
+				_ = sm.GetPrevious()
+				_ = sm.History()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ticks != firers*fires {
+		t.Errorf("actions: want %d got %d", firers*fires, ticks)
+	}
+	if got := len(sm.History()); got != firers*fires {
+		t.Errorf("history: want %d got %d", firers*fires, got)
+	}
+}
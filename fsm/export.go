@@ -0,0 +1,203 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Export a Finite State Machine as a Graphviz DOT or Mermaid diagram.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// a transition as rendered in a diagram
+type diagramEdge struct {
+	from  StateId
+	to    StateId
+	label string
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// write the state machine as a Graphviz DOT digraph. Every state is
+// rendered with its name; terminal states are double-circled and the
+// initial state is pointed at by an entry arrow. Transitions are those
+// declared (see State.AllowTransitions), the timeouts (see
+// State.WithTimeout), the event-driven ones (see AddTransition) and
+// those observed while the machine ran, labeled by their declared
+// label, event, guard names or recorded cause.
+func (sm *StateMachine[T]) ExportDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(sm.name))
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [shape=circle];")
+	for _, state := range sm.States() {
+		shape := ""
+		if state.isTerminal {
+			shape = ", shape=doublecircle"
+		}
+		fmt.Fprintf(bw, "\t%s [label=%s%s];\n", dotNode(state.Id), dotQuote(state.Name), shape)
+	}
+	edges := sm.diagramEdges()
+	if targetsHistory(edges) {
+		fmt.Fprintf(bw, "\t%s [label=\"H\", shape=circle, style=dashed];\n", dotNode(StateHistory))
+	}
+	if sm.initialState != nil {
+		fmt.Fprintln(bw, "\t__start [shape=point];")
+		fmt.Fprintf(bw, "\t__start -> %s;\n", dotNode(sm.initialState.Id))
+	}
+	for _, e := range edges {
+		if len(e.label) == 0 {
+			fmt.Fprintf(bw, "\t%s -> %s;\n", dotNode(e.from), dotNode(e.to))
+		} else {
+			fmt.Fprintf(bw, "\t%s -> %s [label=%s];\n", dotNode(e.from), dotNode(e.to), dotQuote(e.label))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// write the state machine as a Mermaid state diagram. The initial
+// state is entered from [*] and terminal states exit to [*]. The
+// transitions are the same as those of ExportDOT().
+func (sm *StateMachine[T]) ExportMermaid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "stateDiagram-v2")
+	if len(sm.name) != 0 {
+		fmt.Fprintf(bw, "\t%%%% %s\n", sm.name)
+	}
+	states := sm.States()
+	for _, state := range states {
+		fmt.Fprintf(bw, "\t%s : %s\n", mermaidNode(state.Id), mermaidLabel(state.Name))
+	}
+	edges := sm.diagramEdges()
+	if targetsHistory(edges) {
+		fmt.Fprintf(bw, "\t%s : H\n", mermaidNode(StateHistory))
+	}
+	if sm.initialState != nil {
+		fmt.Fprintf(bw, "\t[*] --> %s\n", mermaidNode(sm.initialState.Id))
+	}
+	for _, e := range edges {
+		if len(e.label) == 0 {
+			fmt.Fprintf(bw, "\t%s --> %s\n", mermaidNode(e.from), mermaidNode(e.to))
+		} else {
+			fmt.Fprintf(bw, "\t%s --> %s : %s\n", mermaidNode(e.from), mermaidNode(e.to), mermaidLabel(e.label))
+		}
+	}
+	for _, state := range states {
+		if state.isTerminal {
+			fmt.Fprintf(bw, "\t%s --> [*]\n", mermaidNode(state.Id))
+		}
+	}
+
+	return bw.Flush()
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// merge the declared, the timeout, the event-driven and the observed
+// transitions into a sorted list of unique edges. Declared labels and
+// event names take precedence over the causes recorded at runtime.
+func (sm *StateMachine[T]) diagramEdges() []diagramEdge {
+	labels := make(map[edge][]string)
+	for _, state := range sm.States() {
+		for _, target := range state.transitions {
+			key := edge{state.Id, target}
+			label := strings.TrimSpace(state.labels[target] + " " + strings.Join(state.guardNames(target), " "))
+			if len(label) != 0 {
+				labels[key] = []string{label}
+			} else {
+				labels[key] = nil
+			}
+		}
+		for _, t := range state.timeoutTransitions() {
+			key := edge{state.Id, t.target}
+			if !slices.Contains(labels[key], t.label) {
+				labels[key] = append(labels[key], t.label)
+			}
+		}
+	}
+
+	sm.mu.Lock()
+	for _, t := range sm.events {
+		key := edge{t.From, t.To}
+		if label := eventLabel(t); !slices.Contains(labels[key], label) {
+			labels[key] = append(labels[key], label)
+		}
+	}
+	for key, seen := range sm.observed {
+		if current, declared := labels[key]; declared && len(current) != 0 {
+			continue
+		}
+		labels[key] = append(labels[key], seen.causes...)
+	}
+	sm.mu.Unlock()
+
+	edges := make([]diagramEdge, 0, len(labels))
+	for key, texts := range labels {
+		edges = append(edges, diagramEdge{key.from, key.to, strings.Join(texts, " / ")})
+	}
+	slices.SortFunc(edges, func(a, b diagramEdge) int {
+		if a.from != b.from {
+			return cmp.Compare(a.from, b.from)
+		}
+		return cmp.Compare(a.to, b.to)
+	})
+
+	return edges
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// whether any transition returns to the previous state (StateHistory)
+// which is then rendered as a pseudo-state labeled H.
+func targetsHistory(edges []diagramEdge) bool {
+	for _, e := range edges {
+		if e.to == StateHistory {
+			return true
+		}
+	}
+	return false
+}
+
+// the DOT node identifier of a state
+func dotNode(id StateId) string {
+	return fmt.Sprintf("s%d", id)
+}
+
+// a DOT double-quoted string
+func dotQuote(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+	return `"` + strings.ReplaceAll(text, "\n", `\n`) + `"`
+}
+
+// the Mermaid node identifier of a state
+func mermaidNode(id StateId) string {
+	return fmt.Sprintf("s%d", id)
+}
+
+// Mermaid labels run until the end of line and cannot contain colons
+func mermaidLabel(text string) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	return strings.ReplaceAll(text, ":", "#58;")
+}
@@ -0,0 +1,73 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newExported() *StateMachine[int] {
+	start := NewStateSimple(stStart, "Start", false, nil).
+		AllowTransition(stMiddle, "Next: please").
+		When(stEnd, "vip", 0, nil).
+		WithTimeout(time.Second, stStart)
+	return NewStateMachine[int]("exported", start,
+		NewStateSimple(stMiddle, "Middle", true, nil),
+		NewStateSimple(stEnd, "End", true, nil))
+}
+
+func TestExportDOT(t *testing.T) {
+	var sb strings.Builder
+	if err := newExported().ExportDOT(&sb); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`digraph "exported" {`,
+		`s3 [label="End", shape=doublecircle];`,
+		`__start -> s1;`,
+		`s1 -> s2 [label="Next: please"];`,
+		`s1 -> s3 [label="[vip]"];`,
+		`s1 -> s1 [label="timeout"];`,
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, sb.String())
+		}
+	}
+}
+
+func TestExportMermaid(t *testing.T) {
+	var sb strings.Builder
+	if err := newExported().ExportMermaid(&sb); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"stateDiagram-v2",
+		"[*] --> s1",
+		"s1 --> s2 : Next#58; please",
+		"s1 --> s3 : [vip]",
+		"s1 --> s1 : timeout",
+		"s3 --> [*]",
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, sb.String())
+		}
+	}
+}
+
+func TestExportObservedCauses(t *testing.T) {
+	start := NewStateSimple(stStart, "Start", false, func(im IStateMachine) StateId {
+		im.SetCause("hurry")
+		return stEnd
+	})
+	sm := NewStateMachine[int]("observed", start, NewStateSimple(stEnd, "End", true, nil))
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := sm.ExportDOT(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if want := `s1 -> s3 [label="hurry"];`; !strings.Contains(sb.String(), want) {
+		t.Errorf("missing %q in:\n%s", want, sb.String())
+	}
+}
@@ -0,0 +1,108 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Failing states: bodies that return an error or panic. Failures are
+ * either returned by Start() or routed to an error state.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"errors"
+	"fmt"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	ErrStateFailed = errors.New("state failed")
+	ErrStatePanic  = errors.New("state panicked")
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// Callback function signature for the body of a state that may fail.
+// When it returns an error the state is not exited normally (see
+// StateMachine.SetErrorState).
+type FallibleStateHandler func(IStateMachine) (StateId, error)
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) Creates a new instance of a State whose body may fail by
+// returning an error.
+func NewFallibleState(id StateId, name string, onEnter OnEnterHandler, onExit OnExitHandler, terminal bool, body FallibleStateHandler) *State {
+	state := NewState(id, name, onEnter, onExit, terminal, nil)
+	state.fallible = body
+	return state
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// set the state the machine transitions to when a state fails, that
+// is, its body returns an error (see NewFallibleState) or any of its
+// handlers panics. The failing state's OnExit is not executed and the
+// error is available to the error state with GetError(). Every state
+// may thus transition to the error state, which must exist and be able
+// to reach a terminal state. Without an error state (the default) or
+// when the error state itself fails, Start() returns the error wrapped
+// with the failing state's id and name.
+func (sm *StateMachine[T]) SetErrorState(id StateId) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.errorState = id
+	return sm
+}
+
+// get the failure that sent the machine to its error state (see
+// SetErrorState), else nil.
+func (sm *StateMachine[T]) GetError() error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.lastError
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// decide what to do with an error of a running state: go to the error
+// state (returning its id and nil) or return the error.
+func (sm *StateMachine[T]) divert(state *State, err error) (StateId, error) {
+	if !errors.Is(err, ErrStateFailed) && !errors.Is(err, ErrStatePanic) {
+		return StateNone, err
+	}
+
+	sm.mu.Lock()
+	target := sm.errorState
+	if target == StateNone || target == state.Id {
+		sm.mu.Unlock()
+		return StateNone, err
+	}
+	sm.lastError = err
+	sm.cause = "error"
+	sm.mu.Unlock()
+
+	sm.notifyError(err)
+	return target, nil
+}
+
+// the error of a failed state body
+func (s *State) failed(err error) error {
+	return fmt.Errorf("%s: %w: %w", s.describe(), ErrStateFailed, err)
+}
+
+// the error of a panicking state handler
+func (s *State) panicked(cause any) error {
+	return fmt.Errorf("%s: %w: %v", s.describe(), ErrStatePanic, cause)
+}
@@ -0,0 +1,69 @@
+package fsm
+
+import (
+	"errors"
+	"testing"
+)
+
+var errBroken = errors.New("broken")
+
+func newFailing(start *State) *StateMachine[int] {
+	return NewStateMachine[int]("failing", start,
+		NewStateSimple(stEnd, "End", true, nil),
+		NewStateSimple(stError, "Error", true, nil))
+}
+
+func TestFailureGoesToErrorState(t *testing.T) {
+	exited := false
+	start := NewFallibleState(stStart, "Start", nil, func(IStateMachine) { exited = true }, false,
+		func(IStateMachine) (StateId, error) { return stEnd, errBroken })
+	var notified error
+	sm := newFailing(start).SetErrorState(stError)
+	sm.OnError(func(_ IStateMachine, err error) { notified = err })
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(notified, errBroken) {
+		t.Errorf("OnError: want the failure got %v", notified)
+	}
+	if got := sm.Current(); got != stError {
+		t.Errorf("current: want %d got %d", stError, got)
+	}
+	if err := sm.GetError(); !errors.Is(err, ErrStateFailed) || !errors.Is(err, errBroken) {
+		t.Errorf("GetError: %v", err)
+	}
+	if exited {
+		t.Error("the failing state was exited")
+	}
+}
+
+func TestFailureWithoutErrorState(t *testing.T) {
+	start := NewFallibleState(stStart, "Start", nil, nil, false,
+		func(IStateMachine) (StateId, error) { return stEnd, errBroken })
+	sm := newFailing(start)
+	if err := sm.Start(); !errors.Is(err, errBroken) {
+		t.Fatalf("want the failure got %v", err)
+	}
+	if sm.IsActive() || sm.IsDone() {
+		t.Errorf("status: %s", sm)
+	}
+}
+
+func TestPanicIsRecovered(t *testing.T) {
+	start := NewState(stStart, "Start", func(IStateMachine) { panic("boom") }, nil, false, nil)
+	if err := newFailing(start).Start(); !errors.Is(err, ErrStatePanic) {
+		t.Fatalf("want ErrStatePanic got %v", err)
+	}
+
+	start = NewState(stStart, "Start", func(IStateMachine) { panic("boom") }, nil, false, nil)
+	sm := newFailing(start).SetErrorState(stError)
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(sm.GetError(), ErrStatePanic) {
+		t.Errorf("GetError: %v", sm.GetError())
+	}
+	if got := start.Run(); got != StateNone {
+		t.Errorf("State.Run of a panicking state: want StateNone got %d", got)
+	}
+}
@@ -0,0 +1,162 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Guard conditions on transitions. Guards are named so that they can
+ * be shown in exported diagrams and traces, and are evaluated in
+ * priority order with a fallback to a default target.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	ErrGuardRejected = errors.New("transition rejected by its guard")
+	ErrNoTransition  = errors.New("no guard allowed a transition and there is no default")
+)
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// a guarded transition of a state in blocking (Start) mode
+type guardedTransition struct {
+	target   StateId
+	name     string
+	priority int
+	check    GuardFunc
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// add a named guard to the transition to target (which is also
+// declared, see AllowTransitions). Used in blocking (Start) mode, where
+// the payload given to the guard is the state data:
+//   - if the body returns StateNone, or the state has no body, the
+//     guards are evaluated from the highest to the lowest priority and
+//     the first that allows its transition is taken, else the default
+//     target (see Otherwise) is taken.
+//   - if the body returns a guarded target and none of its guards allow
+//     it, the default target is taken instead, or an error is returned
+//     if there is no default.
+func (s *State) When(target StateId, name string, priority int, guard GuardFunc) *State {
+	if !slices.Contains(s.transitions, target) {
+		s.transitions = append(s.transitions, target)
+	}
+	s.guards = append(s.guards, guardedTransition{target, name, priority, guard})
+	slices.SortStableFunc(s.guards, func(a, b guardedTransition) int {
+		return cmp.Compare(b.priority, a.priority)
+	})
+	return s
+}
+
+// set the default target taken when no guard allows a transition
+// (see When). The target is also declared (see AllowTransitions).
+func (s *State) Otherwise(target StateId) *State {
+	if !slices.Contains(s.transitions, target) {
+		s.transitions = append(s.transitions, target)
+	}
+	s.fallback = target
+	return s
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// whether the state has guarded transitions or a default target
+func (s *State) hasGuards() bool {
+	return len(s.guards) != 0 || s.fallback != StateNone
+}
+
+// apply the guards to the target chosen by the body (or StateNone if
+// the guards should choose) and return the target to transition to.
+func (s *State) resolve(next StateId) (StateId, error) {
+	if !s.hasGuards() {
+		return next, nil
+	}
+
+	data := s.parent.GetStateData()
+	guarded := false
+	for _, g := range s.guards {
+		if next != StateNone && g.target != next {
+			continue
+		}
+		guarded = true
+		if g.check == nil || g.check(s.parent, data) {
+			s.parent.SetCause(guardLabel(g.name))
+			return g.target, nil
+		}
+	}
+	if next != StateNone && !guarded {
+		return next, nil
+	}
+
+	if s.fallback != StateNone {
+		s.parent.SetCause(guardLabel("else"))
+		return s.fallback, nil
+	}
+	if next == StateNone {
+		return next, fmt.Errorf("%s: %w", s.describe(), ErrNoTransition)
+	}
+	return next, fmt.Errorf("%s -> %d: %w", s.describe(), next, ErrGuardRejected)
+}
+
+// the guard names of the transitions to a target, for diagrams
+func (s *State) guardNames(target StateId) []string {
+	names := make([]string, 0)
+	for _, g := range s.guards {
+		if g.target == target && len(g.name) != 0 {
+			names = append(names, guardLabel(g.name))
+		}
+	}
+	if s.fallback == target {
+		names = append(names, guardLabel("else"))
+	}
+	return names
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// a guard name as shown in diagrams and traces: [name]
+func guardLabel(name string) string {
+	if len(name) == 0 {
+		return ""
+	}
+	return "[" + name + "]"
+}
+
+// the label of an event-driven transition: the event and guard name
+func eventLabel(t Transition) string {
+	return strings.TrimSpace(string(t.Event) + " " + guardLabel(t.GuardName))
+}
+
+// order the candidate transitions of an event: guarded ones from the
+// highest to the lowest priority, followed by the unguarded ones which
+// act as the default.
+func sortCandidates(candidates []Transition) {
+	slices.SortStableFunc(candidates, func(a, b Transition) int {
+		if (a.Guard == nil) != (b.Guard == nil) {
+			if a.Guard == nil {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+}
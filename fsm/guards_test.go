@@ -0,0 +1,79 @@
+package fsm
+
+import (
+	"errors"
+	"testing"
+)
+
+// a machine whose start state is decided by guards on the amount
+func newGuarded(start *State) *StateMachine[int] {
+	return NewStateMachine[int]("guarded", start,
+		NewStateSimple(stMiddle, "Middle", true, nil),
+		NewStateSimple(stEnd, "End", true, nil))
+}
+
+func atLeast(n int) GuardFunc {
+	return func(_ IStateMachine, payload any) bool { return *payload.(*int) >= n }
+}
+
+func TestGuardsByPriority(t *testing.T) {
+	for _, tc := range []struct {
+		amount int
+		want   StateId
+	}{
+		{amount: 5, want: stEnd},
+		{amount: 50, want: stMiddle},
+	} {
+		start := NewStateSimple(stStart, "Start", false, nil).
+			When(stMiddle, "big", 2, atLeast(10)).
+			When(stEnd, "any", 1, atLeast(0))
+		sm := newGuarded(start)
+		amount := tc.amount
+		sm.SetUserDataObject(&amount)
+		if err := sm.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if got := sm.Current(); got != tc.want {
+			t.Errorf("amount %d: want %d got %d", tc.amount, tc.want, got)
+		}
+	}
+}
+
+func TestGuardsOtherwise(t *testing.T) {
+	start := NewStateSimple(stStart, "Start", false, nil).
+		When(stMiddle, "big", 0, atLeast(10)).
+		Otherwise(stEnd)
+	sm := newGuarded(start)
+	sm.SetUserDataObject(new(int))
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != stEnd {
+		t.Errorf("current: want %d got %d", stEnd, got)
+	}
+	if cause := sm.History()[0].Cause; cause != "[else]" {
+		t.Errorf("cause: want %q got %q", "[else]", cause)
+	}
+}
+
+func TestGuardRejectsBody(t *testing.T) {
+	start := NewStateSimple(stStart, "Start", false, func(IStateMachine) StateId {
+		return stMiddle
+	}).When(stMiddle, "big", 0, atLeast(10)).AllowTransitions(stEnd)
+	sm := newGuarded(start)
+	sm.SetUserDataObject(new(int))
+	if err := sm.Start(); !errors.Is(err, ErrGuardRejected) {
+		t.Fatalf("want ErrGuardRejected got %v", err)
+	}
+}
+
+func TestGuardsWithoutTransition(t *testing.T) {
+	start := NewStateSimple(stStart, "Start", false, nil).
+		When(stMiddle, "big", 0, atLeast(10)).
+		When(stEnd, "huge", 0, atLeast(100))
+	sm := newGuarded(start)
+	sm.SetUserDataObject(new(int))
+	if err := sm.Start(); !errors.Is(err, ErrNoTransition) {
+		t.Fatalf("want ErrNoTransition got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Safeguards against a running state machine that never reaches a
+ * terminal state: a body that keeps returning its own id or a cycle
+ * between states.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// number of transitions shown in the trail of ErrLimitExceeded
+	TrailLength = 10
+)
+
+var (
+	ErrLimitExceeded = errors.New("state machine limit exceeded")
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// Limits of a single run of the machine (Start). A zero value means
+// no limit, which is the default.
+type Limits struct {
+	MaxSteps           int           // transitions made
+	MaxSelfTransitions int           // consecutive transitions to self
+	MaxDuration        time.Duration // wall time
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// keeps count of a run to enforce the limits
+type limiter struct {
+	Limits
+	steps   int
+	selfs   int
+	started time.Time
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// set the limits enforced while running (see Start). When one is
+// exceeded Start() returns an error wrapping ErrLimitExceeded that
+// includes the most recent transitions.
+func (sm *StateMachine[T]) SetLimits(limits Limits) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.limits = limits
+	return sm
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// a limiter for a new run of the machine
+func (sm *StateMachine[T]) newLimiter() *limiter {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return &limiter{
+		Limits:  sm.limits,
+		steps:   0,
+		selfs:   0,
+		started: time.Now(),
+	}
+}
+
+// the most recent transitions, for example "Menu -> Balance -> Menu"
+func (sm *StateMachine[T]) trail(count int) string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	entries := sm.history[max(0, len(sm.history)-count):]
+	if len(entries) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(entries)+1)
+	names = append(names, trailName(entries[0].From, entries[0].FromName))
+	for _, entry := range entries {
+		names = append(names, trailName(entry.To, entry.ToName))
+	}
+	return strings.Join(names, " -> ")
+}
+
+// count a transition and check it against the limits
+func (l *limiter) check(from, to StateId) error {
+	l.steps++
+	if from == to {
+		l.selfs++
+	} else {
+		l.selfs = 0
+	}
+
+	switch {
+	case l.MaxSteps > 0 && l.steps > l.MaxSteps:
+		return fmt.Errorf("%w: more than %d steps", ErrLimitExceeded, l.MaxSteps)
+	case l.MaxSelfTransitions > 0 && l.selfs > l.MaxSelfTransitions:
+		return fmt.Errorf("%w: state %d transitioned to itself more than %d times", ErrLimitExceeded, to, l.MaxSelfTransitions)
+	case l.MaxDuration > 0 && time.Since(l.started) > l.MaxDuration:
+		return fmt.Errorf("%w: running for more than %v", ErrLimitExceeded, l.MaxDuration)
+	}
+	return nil
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// a state in a trail: its name, else its id
+func trailName(id StateId, name string) string {
+	if len(name) == 0 {
+		return fmt.Sprintf("%d", id)
+	}
+	return name
+}
@@ -0,0 +1,39 @@
+package fsm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// a machine that never finishes on its own
+func newLooping() *StateMachine[int] {
+	loop := NewStateSimple(stStart, "Loop", false, func(IStateMachine) StateId { return stStart })
+	loop.AllowTransitions(stStart, stEnd)
+	return NewStateMachine[int]("looping", loop, NewStateSimple(stEnd, "End", true, nil))
+}
+
+func TestLimits(t *testing.T) {
+	for name, limits := range map[string]Limits{
+		"steps":    {MaxSteps: 20},
+		"self":     {MaxSelfTransitions: 5},
+		"duration": {MaxDuration: 10 * time.Millisecond},
+	} {
+		sm := newLooping().SetLimits(limits)
+		err := sm.Start()
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Fatalf("%s: want ErrLimitExceeded got %v", name, err)
+		}
+		if !strings.Contains(err.Error(), "Loop") {
+			t.Errorf("%s: the trail is missing: %v", name, err)
+		}
+	}
+}
+
+func TestLimitsNotReached(t *testing.T) {
+	sm := newLinear(&recorder{}).SetLimits(Limits{MaxSteps: 2, MaxSelfTransitions: 1})
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+}
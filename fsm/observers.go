@@ -0,0 +1,207 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Machine-level lifecycle listeners so that cross-cutting concerns
+ * (logging, metrics, analytics) need not be put in every state.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import "context"
+
+/* ----------------------------------------------------------------
+ *				I n t e r f a c e s
+ *-----------------------------------------------------------------*/
+
+var _ lifecycle = (*StateMachine[any])(nil)
+
+// implemented by StateMachine[T] so that States (which are not generic)
+// can notify the listeners of their machine and set its context.
+type lifecycle interface {
+	notifyEnter(state *State)
+	notifyExit(state *State)
+	// replace the context of the machine returning the one it had
+	swapContext(ctx context.Context) context.Context
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// Listener of machine-wide events: start and finish
+type MachineListener func(sm IStateMachine)
+
+// Listener of every transition between two states
+type TransitionListener func(sm IStateMachine, from, to StateId)
+
+// Listener of a state being entered or exited
+type StateListener func(sm IStateMachine, state StateId)
+
+// Listener of the errors that stop the machine, send it to its error
+// state or reject an event
+type ErrorListener func(sm IStateMachine, err error)
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// the registered listeners of a machine
+type listeners struct {
+	start      []MachineListener
+	transition []TransitionListener
+	enter      []StateListener
+	exit       []StateListener
+	finish     []MachineListener
+	errs       []ErrorListener
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// register a listener called when the machine starts running, that is
+// by Start(), Begin() or when entering its composite state.
+func (sm *StateMachine[T]) OnStart(listener MachineListener) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.listeners.start = append(sm.listeners.start, listener)
+	return sm
+}
+
+// register a listener called after every transition, including those
+// to self.
+func (sm *StateMachine[T]) OnTransition(listener TransitionListener) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.listeners.transition = append(sm.listeners.transition, listener)
+	return sm
+}
+
+// register a listener called whenever a state is entered, before the
+// state's own OnEnter handler.
+func (sm *StateMachine[T]) OnStateEnter(listener StateListener) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.listeners.enter = append(sm.listeners.enter, listener)
+	return sm
+}
+
+// register a listener called whenever a state is exited, after the
+// state's own OnExit handler.
+func (sm *StateMachine[T]) OnStateExit(listener StateListener) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.listeners.exit = append(sm.listeners.exit, listener)
+	return sm
+}
+
+// register a listener called when the machine reaches a terminal state
+func (sm *StateMachine[T]) OnFinish(listener MachineListener) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.listeners.finish = append(sm.listeners.finish, listener)
+	return sm
+}
+
+// register a listener called with the error that stops the machine,
+// with the failure that sends it to its error state (see SetErrorState)
+// or with which Fire() rejects an event.
+func (sm *StateMachine[T]) OnError(listener ErrorListener) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.listeners.errs = append(sm.listeners.errs, listener)
+	return sm
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// get a snapshot of the listeners so that they are called unlocked
+func (sm *StateMachine[T]) getListeners() listeners {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.listeners
+}
+
+func (sm *StateMachine[T]) notifyStart() {
+	for _, listener := range sm.getListeners().start {
+		listener(sm)
+	}
+}
+
+func (sm *StateMachine[T]) notifyTransition(from, to StateId) {
+	for _, listener := range sm.getListeners().transition {
+		listener(sm, from, to)
+	}
+}
+
+// implements lifecycle
+func (sm *StateMachine[T]) notifyEnter(state *State) {
+	for _, listener := range sm.getListeners().enter {
+		listener(sm, state.Id)
+	}
+}
+
+// implements lifecycle
+func (sm *StateMachine[T]) notifyExit(state *State) {
+	for _, listener := range sm.getListeners().exit {
+		listener(sm, state.Id)
+	}
+}
+
+func (sm *StateMachine[T]) notifyFinish() {
+	for _, listener := range sm.getListeners().finish {
+		listener(sm)
+	}
+}
+
+// notify the error listeners and return the error
+func (sm *StateMachine[T]) notifyError(err error) error {
+	if err != nil {
+		for _, listener := range sm.getListeners().errs {
+			listener(sm, err)
+		}
+	}
+	return err
+}
+
+// implements lifecycle
+func (sm *StateMachine[T]) swapContext(ctx context.Context) context.Context {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	previous := sm.ctx
+	sm.ctx = ctx
+	return previous
+}
+
+// run the OnEnter handler of a state preceded by the listeners of its
+// machine
+func (s *State) enter() {
+	if machine, ok := s.parent.(lifecycle); ok {
+		machine.notifyEnter(s)
+	}
+	if s.onEnter != nil {
+		s.onEnter(s.parent)
+	}
+}
+
+// run the OnExit handler of a state followed by the listeners of its
+// machine
+func (s *State) exit() {
+	if s.onExit != nil {
+		s.onExit(s.parent)
+	}
+	if machine, ok := s.parent.(lifecycle); ok {
+		machine.notifyExit(s)
+	}
+}
@@ -0,0 +1,40 @@
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestListenersOrder(t *testing.T) {
+	r := &recorder{}
+	sm := NewStateMachine[int]("observed",
+		NewStateSimple(stStart, "Start", false, func(IStateMachine) StateId { return stEnd }),
+		NewStateSimple(stEnd, "End", true, nil))
+	sm.OnStart(func(IStateMachine) { r.record("start") }).
+		OnStateEnter(func(_ IStateMachine, id StateId) { r.record(fmt.Sprint("enter ", id)) }).
+		OnStateExit(func(_ IStateMachine, id StateId) { r.record(fmt.Sprint("exit ", id)) }).
+		OnTransition(func(_ IStateMachine, from, to StateId) { r.record(fmt.Sprint(from, " -> ", to)) }).
+		OnFinish(func(IStateMachine) { r.record("finish") }).
+		OnError(func(IStateMachine, error) { r.record("error") })
+
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	assertCalls(t, []string{"start", "enter 1", "exit 1", "1 -> 3", "enter 3", "finish"}, r.get())
+}
+
+func TestErrorListener(t *testing.T) {
+	var got error
+	sm := newEventMachine()
+	sm.AddTransition(Transition{From: stStart, Event: evGo, To: stMiddle})
+	sm.AddTransition(Transition{From: stMiddle, Event: evGo, To: stEnd})
+	sm.OnError(func(_ IStateMachine, err error) { got = err })
+	if err := sm.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	sm.Fire(evTick, nil)
+	if !errors.Is(got, ErrInvalidEvent) {
+		t.Errorf("want ErrInvalidEvent got %v", got)
+	}
+}
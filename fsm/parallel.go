@@ -0,0 +1,122 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Parallel states with orthogonal regions: independent child machines
+ * that progress together while the state is active.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"fmt"
+	"slices"
+)
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) Creates a parallel state whose regions (child machines) run
+// every time the state is run, after its OnEnter. The regions are not
+// concurrent: they progress in lockstep on the caller's goroutine, in
+// every round each region that has not finished executes one state, in
+// the order given, so that the callbacks are always made in the same
+// order. A state that blocks (e.g. waiting for an answer) therefore
+// holds up the other regions until it returns. When every region has reached a terminal state, the body (if
+// not nil) decides the next state, else the guards (see When). The
+// regions must reach their own terminal states; a transition to a
+// state of the outer machine is an error. Like composite states, a
+// region without state data shares that of the outer machine when
+// both have the same data type.
+func NewParallelState(id StateId, name string, onEnter OnEnterHandler, onExit OnExitHandler, body StateMainHandler, regions ...ISubMachine) *State {
+	state := NewState(id, name, onEnter, onExit, false, body)
+	state.regions = slices.Clone(regions)
+	return state
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// get the regions of a parallel state, else nil.
+func (s *State) Regions() []ISubMachine {
+	return slices.Clone(s.regions)
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements ISubMachine
+func (sm *StateMachine[T]) beginRegion(outer IStateMachine) {
+	start := sm.enterNested(outer, false)
+	sm.notifyStart()
+
+	cursor := sm.newCursor(start)
+	sm.mu.Lock()
+	sm.stepping = cursor
+	sm.mu.Unlock()
+}
+
+// implements ISubMachine
+func (sm *StateMachine[T]) stepRegion() (bool, error) {
+	sm.mu.Lock()
+	cursor := sm.stepping
+	sm.mu.Unlock()
+
+	if cursor == nil {
+		return true, nil
+	}
+	_, done, _, err := sm.advance(cursor, nil)
+	if done || err != nil {
+		sm.endRegion()
+	}
+	return done, err
+}
+
+// implements ISubMachine
+func (sm *StateMachine[T]) endRegion() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.stepping = nil
+	sm.isActive = false
+}
+
+// run the regions of a parallel state until all of them are done and
+// decide the next state
+func (s *State) runRegions() (StateId, error) {
+	for _, region := range s.regions {
+		region.beginRegion(s.parent)
+	}
+	defer func() {
+		for _, region := range s.regions {
+			region.endRegion()
+		}
+	}()
+
+	pending := slices.Clone(s.regions)
+	for len(pending) != 0 {
+		running := make([]ISubMachine, 0, len(pending))
+		for _, region := range pending {
+			done, err := region.stepRegion()
+			if err != nil {
+				return StateNone, fmt.Errorf("%s: region %q: %w", s.describe(), region.GetName(), err)
+			}
+			if !done {
+				running = append(running, region)
+			}
+		}
+		pending = running
+	}
+
+	// every region reached a terminal state
+	if s.body != nil {
+		return s.body(s.parent), nil
+	}
+	if s.hasGuards() {
+		return StateNone, nil
+	}
+	return StateNone, fmt.Errorf("%s: regions finished: %w", s.describe(), ErrNoTransition)
+}
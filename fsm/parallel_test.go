@@ -0,0 +1,55 @@
+package fsm
+
+import (
+	"errors"
+	"testing"
+)
+
+func newRegion(r *recorder, prefix string, first StateId, steps int) *StateMachine[int] {
+	states := make([]*State, 0, steps)
+	for i := 0; i < steps; i++ {
+		id := first + StateId(i)
+		next := id + 1
+		if i == steps-1 {
+			next = id
+		}
+		name := prefix + string(rune('1'+i))
+		states = append(states, NewStateSimple(id, name, i == steps-1, func(IStateMachine) StateId {
+			r.record(name)
+			return next
+		}))
+	}
+	return NewStateMachine[int](prefix, states[0], states[1:]...)
+}
+
+func TestParallelRegionsInLockstep(t *testing.T) {
+	r := &recorder{}
+	parallel := NewParallelState(stStart, "Parallel", nil, nil,
+		func(IStateMachine) StateId { return stEnd },
+		newRegion(r, "a", 10, 3), newRegion(r, "b", 20, 2))
+	sm := NewStateMachine[int]("parallel", parallel, NewStateSimple(stEnd, "End", true, nil))
+
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	assertCalls(t, []string{"a1", "b1", "a2", "b2", "a3"}, r.get())
+	if got := sm.Current(); got != stEnd {
+		t.Errorf("current: want %d got %d", stEnd, got)
+	}
+	if got := len(parallel.Regions()); got != 2 {
+		t.Errorf("regions: want 2 got %d", got)
+	}
+}
+
+func TestParallelRegionLeaving(t *testing.T) {
+	region := NewStateMachine[int]("region",
+		NewStateSimple(10, "Leave", false, func(IStateMachine) StateId { return stEnd }),
+		NewStateSimple(11, "Done", true, nil))
+	sm := NewStateMachine[int]("parallel",
+		NewParallelState(stStart, "Parallel", nil, nil, func(IStateMachine) StateId { return stEnd }, region),
+		NewStateSimple(stEnd, "End", true, nil))
+
+	if err := sm.Start(); !errors.Is(err, ErrUnknownState) {
+		t.Fatalf("want ErrUnknownState got %v", err)
+	}
+}
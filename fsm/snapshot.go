@@ -0,0 +1,141 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Snapshot and restore of a running state machine so that a session
+ * can be persisted and resumed, for example after a restart.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+const (
+	// version of the snapshot format written by Snapshot()
+	SnapshotVersion int = 1
+)
+
+var (
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+	ErrSnapshotMachine = errors.New("snapshot belongs to another state machine")
+)
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// the persisted form of a state machine
+type snapshot[T any] struct {
+	Version  int          `json:"version"`
+	Machine  string       `json:"machine"`
+	Current  StateId      `json:"current"`
+	Previous StateId      `json:"previous"`
+	Finished bool         `json:"finished"`
+	History  []TraceEntry `json:"history"`
+	Data     *T           `json:"data,omitempty"`
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// take a JSON snapshot of the machine: its current and previous states,
+// the transition history and the state data (which must therefore be
+// serializable with encoding/json). The states of child machines of
+// composite states are not included.
+func (sm *StateMachine[T]) Snapshot() ([]byte, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	snap := snapshot[T]{
+		Version:  SnapshotVersion,
+		Machine:  sm.name,
+		Current:  StateNone,
+		Previous: sm.previousState,
+		Finished: sm.isFinished,
+		History:  sm.history,
+		Data:     sm.stateData,
+	}
+	if sm.current != nil {
+		snap.Current = sm.current.Id
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("state machine %q: snapshot: %w", sm.name, err)
+	}
+	return data, nil
+}
+
+// restore a snapshot taken with Snapshot() on a machine with the same
+// name and states. The next Start() or Begin() resumes the restored
+// current state, unless the snapshot was taken of a finished machine,
+// in which case it starts over at the initial state. It must not be
+// called while the machine is active.
+func (sm *StateMachine[T]) Restore(data []byte) error {
+	var snap snapshot[T]
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("state machine %q: restore: %w", sm.name, err)
+	}
+	if snap.Version < 1 || snap.Version > SnapshotVersion {
+		return fmt.Errorf("state machine %q: restore version %d: %w", sm.name, snap.Version, ErrSnapshotVersion)
+	}
+	if snap.Machine != sm.name {
+		return fmt.Errorf("state machine %q: restore %q: %w", sm.name, snap.Machine, ErrSnapshotMachine)
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	current, ok := sm.states[snap.Current]
+	if !ok && snap.Current != StateNone {
+		return fmt.Errorf("state machine %q: restore current state %d: %w", sm.name, snap.Current, ErrUnknownState)
+	}
+
+	sm.current = current
+	sm.previousState = snap.Previous
+	sm.isFinished = snap.Finished
+	sm.restored = current != nil && !snap.Finished
+	if snap.Data != nil {
+		sm.stateData = snap.Data
+	}
+
+	// the observed transitions (for diagrams) are rebuilt from history
+	sm.history = make([]TraceEntry, 0, len(snap.History))
+	sm.observed = make(map[edge]*observation)
+	for _, entry := range snap.History {
+		sm.history = append(sm.history, entry)
+		sm.countEdge(entry.From, entry.To, entry.Cause)
+	}
+	sm.since = time.Now()
+	return nil
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// get the state to start running at: the restored state (once) else the
+// initial state, in which case the previous state is cleared. The
+// failure of a previous run is forgotten.
+func (sm *StateMachine[T]) resumeState() *State {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.lastError = nil
+	if sm.restored {
+		sm.restored = false
+		return sm.current
+	}
+	sm.previousState = StateNone
+	return sm.initialState
+}
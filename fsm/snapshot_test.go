@@ -0,0 +1,83 @@
+package fsm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	// snapshot in the middle state, then stop by going to an unknown state
+	var data []byte
+	var sm *StateMachine[int]
+	middle := NewStateSimple(stMiddle, "Middle", false, func(IStateMachine) StateId {
+		data, _ = sm.Snapshot()
+		return stError
+	})
+	sm = NewStateMachine[int]("linear",
+		NewStateSimple(stStart, "Start", false, func(IStateMachine) StateId { return stMiddle }),
+		middle, NewStateSimple(stEnd, "End", true, nil))
+	sm.SetUserDataObject(new(int))
+	*sm.Data() = 42
+	if err := sm.Start(); !errors.Is(err, ErrUnknownState) {
+		t.Fatalf("want ErrUnknownState got %v", err)
+	}
+
+	r := &recorder{}
+	restored := newLinear(r)
+	if err := restored.Restore(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Current(); got != stMiddle {
+		t.Errorf("current: want %d got %d", stMiddle, got)
+	}
+	if got := *restored.Data(); got != 42 {
+		t.Errorf("data: want 42 got %d", got)
+	}
+	if err := restored.Start(); err != nil {
+		t.Fatal(err)
+	}
+	assertCalls(t, []string{
+		"enter Middle", "body Middle", "exit Middle",
+		"enter End", "body End",
+	}, r.get())
+	if got := len(restored.History()); got != 2 {
+		t.Errorf("history: want 2 transitions got %d", got)
+	}
+}
+
+func TestRestoreFinishedStartsOver(t *testing.T) {
+	sm := newLinear(&recorder{})
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := sm.Snapshot()
+
+	r := &recorder{}
+	restored := newLinear(r)
+	if err := restored.Restore(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.get(); got[0] != "enter Start" {
+		t.Errorf("did not start over: %q", got)
+	}
+}
+
+func TestRestoreErrors(t *testing.T) {
+	sm := newLinear(&recorder{})
+	data, _ := sm.Snapshot()
+
+	other := NewStateMachine[int]("other", NewStateSimple(stStart, "Start", false, nil),
+		NewStateSimple(stEnd, "End", true, nil))
+	if err := other.Restore(data); !errors.Is(err, ErrSnapshotMachine) {
+		t.Errorf("want ErrSnapshotMachine got %v", err)
+	}
+	if err := sm.Restore([]byte(`{"version":99,"machine":"linear"}`)); !errors.Is(err, ErrSnapshotVersion) {
+		t.Errorf("want ErrSnapshotVersion got %v", err)
+	}
+	if err := sm.Restore([]byte(`{"version":1,"machine":"linear","current":9}`)); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("want ErrUnknownState got %v", err)
+	}
+}
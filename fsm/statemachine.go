@@ -8,8 +8,11 @@
 package fsm
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 )
 
 /* ----------------------------------------------------------------
@@ -35,6 +38,13 @@ type IStateMachine interface {
 	IsDone() bool
 	// get the state data. The caller must cast it to the proper type
 	GetStateData() any
+	// record why the current state is about to transition (for example
+	// the text of the chosen option). Used to label exported diagrams.
+	SetCause(cause string)
+	// get the failure that sent the machine to its error state, if any
+	GetError() error
+	// get the context the machine is running with (see Run)
+	Context() context.Context
 }
 
 /* ----------------------------------------------------------------
@@ -46,18 +56,49 @@ type IStateMachine interface {
 type StateMachine[T any] struct {
 	name          string
 	states        map[StateId]*State
+	duplicates    []StateId // ids of states given more than once
+	nilStates     int       // number of nil states given
 	isActive      bool
 	isFinished    bool
 	initialState  *State
+	current       *State // the state being executed
 	previousState StateId
 	stateData     *T
+	cause         string                // cause of the upcoming transition
+	observed      map[edge]*observation // transitions seen at runtime
+	events        []Transition          // transitions of the event-driven mode
+	outer         IStateMachine         // outer machine when nested in a composite state
+	outerKnows    func(StateId) bool    // whether a state is known by the outer machines
+	history       []TraceEntry          // every transition made
+	since         time.Time             // time of the last transition
+	tracer        Tracer                // receives every transition (optional)
+	listeners     listeners             // lifecycle listeners (see OnStart etc.)
+	restored      bool                  // resume from current (see Restore)
+	limits        Limits                // safeguards of a run (see SetLimits)
+	errorState    StateId               // where failing states go (see SetErrorState)
+	lastError     error                 // the failure that led to the error state
+	ctx           context.Context       // context of the current run (see Run)
+	stepping      *cursor               // position when executed by Step()
 	mu            sync.Mutex
+	fireMu        sync.Mutex // serializes Fire()
 }
 
 /* ----------------------------------------------------------------
  *				P r i v a t e	T y p e s
  *-----------------------------------------------------------------*/
 
+// a transition between two states
+type edge struct {
+	from StateId
+	to   StateId
+}
+
+// what has been observed of a transition at runtime
+type observation struct {
+	count  int      // number of times the transition was made
+	causes []string // distinct causes recorded with SetCause()
+}
+
 /* ----------------------------------------------------------------
  *				C o n s t r u c t o r s
  *-----------------------------------------------------------------*/
@@ -68,17 +109,45 @@ func NewStateMachine[T any](name string, initialState *State, otherStates ...*St
 	me := &StateMachine[T]{
 		name:          name,
 		states:        make(map[StateId]*State),
+		duplicates:    make([]StateId, 0),
+		nilStates:     0,
 		isActive:      false,
 		isFinished:    false,
 		initialState:  initialState,
+		current:       nil,
 		previousState: StateNone,
 		stateData:     nil,
+		cause:         "",
+		observed:      make(map[edge]*observation),
+		events:        make([]Transition, 0),
+		outer:         nil,
+		outerKnows:    nil,
+		history:       make([]TraceEntry, 0),
+		since:         time.Time{},
+		tracer:        nil,
+		listeners:     listeners{},
+		restored:      false,
+		limits:        Limits{},
+		errorState:    StateNone,
+		lastError:     nil,
+		ctx:           context.Background(),
+		stepping:      nil,
 	}
 
 	// compose the list of states
-	initialState.parent = me
-	me.states[initialState.Id] = initialState
+	if initialState != nil {
+		initialState.parent = me
+		me.states[initialState.Id] = initialState
+	}
 	for _, state := range otherStates {
+		if state == nil {
+			me.nilStates++
+			continue
+		}
+		if _, exists := me.states[state.Id]; exists {
+			me.duplicates = append(me.duplicates, state.Id)
+			continue
+		}
 		state.parent = me // assign parent FSM (this instance)
 		me.states[state.Id] = state
 	}
@@ -98,26 +167,17 @@ func (sm *StateMachine[T]) String() string {
 	return fmt.Sprintf("[%s] active:%t done:%t", sm.name, sm.isActive, sm.isFinished)
 }
 
-// integrity check that the defined state machine is good. Must be checked
-// prior to Start()
+// integrity check that the defined state machine is good. It is
+// automatically checked by Start(). Besides the number of states and
+// the presence of a terminal state, it detects duplicate ids, declared
+// transitions to missing states, unreachable states and states that
+// cannot reach any terminal state. All problems found are returned
+// joined (see errors.Join) and can be tested with errors.Is().
 func (sm *StateMachine[T]) IsValid() error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	if len(sm.states) == 0 {
-		return fmt.Errorf("no states have been defined")
-	}
-	if len(sm.states) == 1 {
-		return fmt.Errorf("only has an initial state")
-	}
-
-	for _, state := range sm.states {
-		if state.isTerminal {
-			return nil
-		}
-	}
-
-	return fmt.Errorf("there must be at least one terminal state")
+	return sm.validate(nil)
 }
 
 // get the FSM's friendly name
@@ -125,8 +185,9 @@ func (sm *StateMachine[T]) GetName() string {
 	return sm.name
 }
 
-// get the previous StateId. It can be used within State.OnEnter
-// to check what the previous state was.
+// get the previous StateId, that is the state the current state was
+// entered from. Transitions to self do not change it. It can be used
+// within State.OnEnter to check what the previous state was.
 func (sm *StateMachine[T]) GetPrevious() StateId {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
@@ -159,6 +220,16 @@ func (sm *StateMachine[T]) GetStateData() any {
 	return sm.stateData
 }
 
+// record why the current state is about to transition, for example
+// the text of the option the user chose. The cause labels the
+// transition when the machine is exported (see ExportDOT()).
+func (sm *StateMachine[T]) SetCause(cause string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.cause = cause
+}
+
 // Set the custom state data
 func (sm *StateMachine[T]) SetUserDataObject(userData *T) *StateMachine[T] {
 	sm.mu.Lock()
@@ -168,31 +239,208 @@ func (sm *StateMachine[T]) SetUserDataObject(userData *T) *StateMachine[T] {
 	return sm
 }
 
-// Start executing the State machine
-func (sm *StateMachine[T]) Start() {
+// get the initial state
+func (sm *StateMachine[T]) GetInitial() *State {
+	return sm.initialState
+}
+
+// get all the states of the machine ordered by their id. Useful for
+// tools that analyze or export the machine.
+func (sm *StateMachine[T]) States() []*State {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	states := make([]*State, 0, len(sm.states))
+	for _, id := range sm.sortedIds() {
+		states = append(states, sm.states[id])
+	}
+	return states
+}
+
+// Start executing the State machine. The machine is validated first
+// (see IsValid()) and an error is returned if it is not sound, if
+// a state transitions to an unknown state or makes a transition it
+// did not declare (see State.AllowTransitions) while running. A
+// machine restored from a snapshot (see Restore) resumes its current
+// state, whose OnEnter runs again. Same as Run(context.Background()).
+func (sm *StateMachine[T]) Start() error {
+	return sm.Run(context.Background())
+}
+
+// Start executing the State machine (see Start) until it finishes or
+// the context is done. Cancellation is checked in between states, so a
+// state that is waiting for input is not interrupted unless its body
+// watches Context() itself. When cancelled, the OnExit of a state that
+// has not been exited yet is executed and an error wrapping the cause
+// of the cancellation (e.g. context.Canceled) is returned.
+func (sm *StateMachine[T]) Run(ctx context.Context) error {
+	if err := sm.IsValid(); err != nil {
+		return sm.notifyError(fmt.Errorf("state machine %q is invalid: %w", sm.name, err))
+	}
+
+	start := sm.resumeState()
+	sm.mu.Lock()
+	sm.ctx = ctx
 	sm.isActive = true
 	sm.isFinished = false
+	sm.since = time.Now()
+	sm.mu.Unlock()
+	defer func() { sm.setStatus(false, sm.IsDone()) }()
 
-	// the initial state is always executed first
-	nextState := sm.initialState.Run()
-	// update because InitialState previous's is StateNone
-	sm.previousState = sm.initialState.Id
-
-	// Loop through the defined states transitions
-	for !sm.isFinished {
-		currentState := sm.states[nextState]
-		nextState = currentState.Run()
-		// update the previous state if we are transitioning
-		if nextState != sm.previousState {
-			sm.previousState = currentState.Id
-		}
-		// check if terminating by FSM definition
-		sm.isFinished = currentState.isTerminal
-	}
+	sm.notifyStart()
+	_, _, err := sm.loop(start, nil)
+	return err
+}
+
+// get the context the machine is running with. It is the context given
+// to Run(), that of the outer machine when nested in a composite state,
+// else context.Background().
+func (sm *StateMachine[T]) Context() context.Context {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 
-	sm.isActive = false
+	return sm.ctx
 }
 
 /* ----------------------------------------------------------------
  *				P r i v a t e	M e t h o d s
  *-----------------------------------------------------------------*/
+
+// run the states, beginning with the start state, until a terminal
+// state has been executed. In a nested machine (see NewCompositeState)
+// the loop also ends when a state transitions to a state known by the
+// outer machines, which is returned along with bubbled=true.
+func (sm *StateMachine[T]) loop(start *State, outer func(StateId) bool) (next StateId, bubbled bool, err error) {
+	cursor := sm.newCursor(start)
+	for {
+		next, done, bubbled, err := sm.advance(cursor, outer)
+		if done || bubbled || err != nil {
+			return next, bubbled, err
+		}
+	}
+}
+
+// run the state at the cursor and move the cursor to the next state.
+// It is done when a terminal state has been executed.
+func (sm *StateMachine[T]) advance(c *cursor, outer func(StateId) bool) (next StateId, done bool, bubbled bool, err error) {
+	// OnEnter only runs when coming from another state, which
+	// becomes the previous state
+	entering := c.current != c.last
+	if c.ctx.Err() != nil {
+		if !entering {
+			c.current.exit()
+		}
+		return StateNone, false, false, sm.notifyError(fmt.Errorf("state machine %q: stopped in %s: %w", sm.name, c.current.describe(), context.Cause(c.ctx)))
+	}
+	if entering && c.last != nil {
+		sm.setPrevious(c.last.Id)
+	}
+	sm.setCurrent(c.current)
+	nextState, err := c.current.run(entering)
+	if err != nil && c.ctx.Err() != nil {
+		// stopped while running the state (e.g. a nested machine)
+		c.current.exit()
+		return nextState, false, false, sm.notifyError(fmt.Errorf("state machine %q: %w", sm.name, err))
+	} else if err != nil {
+		if nextState, err = sm.divert(c.current, err); err != nil {
+			return nextState, false, false, sm.notifyError(fmt.Errorf("state machine %q: %w", sm.name, err))
+		}
+	} else if c.current.isTerminal {
+		// terminating by FSM definition
+		sm.setStatus(true, true)
+		sm.notifyFinish()
+		return nextState, true, false, nil
+	}
+	sm.observe(c.current.Id, nextState)
+	if err := c.limiter.check(c.current.Id, nextState); err != nil {
+		return nextState, false, false, sm.notifyError(fmt.Errorf("state machine %q: %w; trail: %s", sm.name, err, sm.trail(TrailLength)))
+	}
+
+	nextOne, ok := sm.states[nextState]
+	if !ok {
+		if outer != nil && outer(nextState) {
+			return nextState, false, true, nil
+		}
+		return nextState, false, false, sm.notifyError(fmt.Errorf("state machine %q: %s transitioned to state %d: %w", sm.name, c.current.describe(), nextState, ErrUnknownState))
+	}
+	c.last, c.current = c.current, nextOne
+	sm.setCurrent(nextOne)
+	return nextState, false, false, nil
+}
+
+// set the state the current state was entered from
+func (sm *StateMachine[T]) setPrevious(id StateId) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.previousState = id
+}
+
+// set whether the machine is running and whether it has finished
+func (sm *StateMachine[T]) setStatus(active, finished bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.isActive = active
+	sm.isFinished = finished
+}
+
+// set the state being executed
+func (sm *StateMachine[T]) setCurrent(state *State) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.current = state
+}
+
+// record a transition made at runtime along with its cause (if any)
+// and clear the cause for the next transition.
+func (sm *StateMachine[T]) observe(from, to StateId) {
+	sm.mu.Lock()
+	sm.countEdge(from, to, sm.cause)
+
+	// add it to the history and pass it on to the tracer
+	now := time.Now()
+	entry := TraceEntry{
+		Machine:  sm.name,
+		From:     from,
+		FromName: sm.stateName(from),
+		To:       to,
+		ToName:   sm.stateName(to),
+		At:       now,
+		Duration: now.Sub(sm.since),
+		Cause:    sm.cause,
+	}
+	sm.history = append(sm.history, entry)
+	sm.since = now
+	sm.cause = ""
+	tracer := sm.tracer
+	sm.mu.Unlock()
+
+	if tracer != nil {
+		tracer.Trace(entry)
+	}
+	sm.notifyTransition(from, to)
+}
+
+// count an observed transition and its cause. Called with mu locked.
+func (sm *StateMachine[T]) countEdge(from, to StateId, cause string) {
+	key := edge{from, to}
+	seen, ok := sm.observed[key]
+	if !ok {
+		seen = &observation{count: 0, causes: make([]string, 0)}
+		sm.observed[key] = seen
+	}
+	seen.count++
+	if len(cause) != 0 && !slices.Contains(seen.causes, cause) {
+		seen.causes = append(seen.causes, cause)
+	}
+}
+
+// get the name of a state or an empty string if unknown
+func (sm *StateMachine[T]) stateName(id StateId) string {
+	if state, ok := sm.states[id]; ok {
+		return state.Name
+	}
+	return ""
+}
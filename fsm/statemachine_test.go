@@ -0,0 +1,177 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+const (
+	stStart StateId = iota + 1
+	stMiddle
+	stEnd
+	stError
+)
+
+// records the handlers executed by the states it creates
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.calls...)
+}
+
+func (r *recorder) state(id StateId, name string, terminal bool, next StateId) *State {
+	return NewState(id, name,
+		func(IStateMachine) { r.record("enter " + name) },
+		func(IStateMachine) { r.record("exit " + name) },
+		terminal,
+		func(IStateMachine) StateId { r.record("body " + name); return next })
+}
+
+// a machine that goes from start to middle to end
+func newLinear(r *recorder) *StateMachine[int] {
+	return NewStateMachine[int]("linear",
+		r.state(stStart, "Start", false, stMiddle),
+		r.state(stMiddle, "Middle", false, stEnd),
+		r.state(stEnd, "End", true, stEnd))
+}
+
+func assertCalls(t *testing.T, want, got []string) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("calls:\nwant %q\ngot  %q", want, got)
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("calls:\nwant %q\ngot  %q", want, got)
+		}
+	}
+}
+
+func TestStartRunsStatesInOrder(t *testing.T) {
+	r := &recorder{}
+	sm := newLinear(r)
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	assertCalls(t, []string{
+		"enter Start", "body Start", "exit Start",
+		"enter Middle", "body Middle", "exit Middle",
+		"enter End", "body End",
+	}, r.get())
+	if !sm.IsDone() || sm.IsActive() {
+		t.Errorf("status: %s", sm)
+	}
+	if got := len(sm.History()); got != 2 {
+		t.Errorf("history: want 2 transitions got %d", got)
+	}
+}
+
+func TestRunCancelledExitsState(t *testing.T) {
+	r := &recorder{}
+	running := make(chan struct{})
+	var once sync.Once
+	waiting := NewState(stStart, "Waiting",
+		func(IStateMachine) { r.record("enter Waiting") },
+		func(IStateMachine) { r.record("exit Waiting") },
+		false,
+		func(im IStateMachine) StateId {
+			once.Do(func() { close(running) })
+			<-im.Context().Done()
+			return stStart
+		})
+	sm := NewStateMachine[int]("cancel", waiting, NewStateSimple(stEnd, "End", true, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- sm.Run(ctx) }()
+	<-running
+	if !sm.IsActive() || sm.Current() != stStart {
+		t.Errorf("while running: %s in %d", sm, sm.Current())
+	}
+	cancel()
+
+	err := <-done
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled got %v", err)
+	}
+	assertCalls(t, []string{"enter Waiting", "exit Waiting"}, r.get())
+	if sm.IsActive() || sm.IsDone() {
+		t.Errorf("status after cancel: %s", sm)
+	}
+}
+
+func TestRunCancelledBeforeStart(t *testing.T) {
+	r := &recorder{}
+	sm := newLinear(r)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sm.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled got %v", err)
+	}
+	if got := r.get(); len(got) != 0 {
+		t.Errorf("handlers executed: %q", got)
+	}
+}
+
+func TestGettersWhileRunning(t *testing.T) {
+	const loops = 200
+	count := 0
+	spin := NewStateSimple(stStart, "Spin", false, func(im IStateMachine) StateId {
+		im.SetCause("spin")
+		if count++; count < loops {
+			return stStart
+		}
+		return stEnd
+	})
+	sm := NewStateMachine[int]("spin", spin, NewStateSimple(stEnd, "End", true, nil))
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_ = sm.Current()
+				_ = sm.IsActive()
+				_ = sm.IsDone()
+				_ = sm.GetPrevious()
+				_ = sm.History()
+				_ = sm.String()
+				if _, err := sm.Snapshot(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	err := sm.Start()
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sm.History()); got != loops {
+		t.Errorf("history: want %d transitions got %d", loops, got)
+	}
+}
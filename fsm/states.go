@@ -8,15 +8,20 @@
  *-----------------------------------------------------------------*/
 package fsm
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
 
 /* ----------------------------------------------------------------
  *						G l o b a l s
  *-----------------------------------------------------------------*/
 
 const (
-	StateNone  StateId = StateId(0)
-	StateFinal StateId = StateId(65535) // pre-defined
+	StateNone    StateId = StateId(0)
+	StateFinal   StateId = StateId(65535) // pre-defined
+	StateHistory StateId = StateId(65534) // pseudo-state: return to the previous state
 
 /*
 // These are user-provided in his/her module
@@ -52,13 +57,23 @@ type StateMainHandler func(IStateMachine) StateId
 
 // An object representing a finite state machine's State.
 type State struct {
-	Id         StateId          // unique state identifier
-	Name       string           // friendly name for the state
-	parent     IStateMachine    // parent state machine
-	onEnter    OnEnterHandler   // always executed prior to body on every State.Run()
-	body       StateMainHandler // the main logic of the State
-	onExit     OnExitHandler    // executed after Body but ONLY if there is a state transition
-	isTerminal bool             // true if this is a terminal (end) state
+	Id          StateId                   // unique state identifier
+	Name        string                    // friendly name for the state
+	parent      IStateMachine             // parent state machine
+	onEnter     OnEnterHandler            // always executed prior to body on every State.Run()
+	body        StateMainHandler          // the main logic of the State
+	onExit      OnExitHandler             // executed after Body but ONLY if there is a state transition
+	isTerminal  bool                      // true if this is a terminal (end) state
+	transitions []StateId                 // declared target states (optional)
+	labels      map[StateId]string        // labels of the declared transitions
+	typeCheck   func(IStateMachine) error // verifies the parent's data type (typed states)
+	guards      []guardedTransition       // guarded transitions (see When)
+	fallback    StateId                   // default target when no guard allows (see Otherwise)
+	nested      ISubMachine               // child machine of a composite state
+	history     bool                      // resume the last active child state (composite)
+	fallible    FallibleStateHandler      // body that may fail (see NewFallibleState)
+	timeout     timeout                   // time limit of the body (see WithTimeout)
+	regions     []ISubMachine             // orthogonal regions of a parallel state
 }
 
 /* ----------------------------------------------------------------
@@ -68,13 +83,23 @@ type State struct {
 // (ctor) Creates a new instance of a State.
 func NewState(id StateId, name string, onEnter OnEnterHandler, onExit OnExitHandler, terminal bool, body StateMainHandler) *State {
 	return &State{
-		Id:         id,
-		Name:       name,
-		parent:     nil,
-		onEnter:    onEnter,
-		body:       body,
-		onExit:     onExit,
-		isTerminal: terminal,
+		Id:          id,
+		Name:        name,
+		parent:      nil,
+		onEnter:     onEnter,
+		body:        body,
+		onExit:      onExit,
+		isTerminal:  terminal,
+		transitions: make([]StateId, 0),
+		labels:      make(map[StateId]string),
+		typeCheck:   nil,
+		guards:      make([]guardedTransition, 0),
+		fallback:    StateNone,
+		nested:      nil,
+		history:     false,
+		fallible:    nil,
+		timeout:     timeout{},
+		regions:     nil,
 	}
 }
 
@@ -93,25 +118,145 @@ func (s *State) String() string {
 	return s.Name
 }
 
-// executes a state
+// declare the states this state may transition to. The declaration is
+// optional; when present it is used by StateMachine.IsValid() to check
+// the graph structure. Transitions to self are always allowed. Declare
+// StateHistory if the state returns to whichever state invoked it.
+func (s *State) AllowTransitions(targets ...StateId) *State {
+	s.transitions = append(s.transitions, targets...)
+	return s
+}
+
+// declare a single transition with a label (for example the text of
+// the option the user chooses) that is used when exporting diagrams.
+func (s *State) AllowTransition(target StateId, label string) *State {
+	if !slices.Contains(s.transitions, target) {
+		s.transitions = append(s.transitions, target)
+	}
+	s.labels[target] = label
+	return s
+}
+
+// get the label of a declared transition, if any.
+func (s *State) TransitionLabel(target StateId) string {
+	return s.labels[target]
+}
+
+// get the states this state declared it may transition to (see
+// AllowTransitions). An empty list means the transitions were not
+// declared and the state may transition anywhere.
+func (s *State) Transitions() []StateId {
+	return slices.Clone(s.transitions)
+}
+
+// whether the state declared the transition to the target state.
+// Transitions to self, the timeout transitions (see WithTimeout) and
+// any transition of a state that did not declare its transitions, are
+// always allowed.
+func (s *State) CanTransitionTo(target StateId) bool {
+	return target == s.Id || len(s.transitions) == 0 || slices.Contains(s.transitions, target) ||
+		s.isTimeoutTarget(target)
+}
+
+// whether this is a terminal (end) state
+func (s *State) IsTerminal() bool {
+	return s.isTerminal
+}
+
+// executes a state as if it were entered from another state. If the
+// body returns a state that was not declared with AllowTransitions(),
+// OnExit is not executed; the state machine reports that as an error
+// when it runs the state. If the state fails (see NewFallibleState) or
+// panics, StateNone is returned.
 func (s *State) Run() StateId {
+	nextState, err := s.run(true)
+	if errors.Is(err, ErrStateFailed) || errors.Is(err, ErrStatePanic) {
+		return StateNone
+	}
+	return nextState
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// executes a state: OnEnter (only when entering from another state),
+// the body, the guards (if any) and OnExit (only when transitioning
+// out). A StateHistory target is resolved to the previous state. An
+// error is returned if the body requests a transition that was not
+// declared or that its guards reject.
+func (s *State) run(entering bool) (next StateId, err error) {
+	defer func() {
+		if cause := recover(); cause != nil {
+			next, err = StateNone, s.panicked(cause)
+		}
+	}()
+
 	// OnEnter is only executed upon the first transition
-	if s.parent.GetPrevious() != s.Id && s.onEnter != nil {
-		s.onEnter(s.parent)
+	if entering {
+		s.enter()
+	}
+
+	// always execute the body of the state. A state without
+	// body but with guards lets its guards decide.
+	var nextState StateId
+	if s.nested != nil {
+		nextState, err = s.runNested()
+	} else if len(s.regions) != 0 {
+		nextState, err = s.runRegions()
+	} else if s.timeout.limit > 0 {
+		nextState, err = s.timed()
+	} else {
+		nextState, err = s.invoke()
+	}
+	if err != nil {
+		return nextState, err
 	}
 
-	// always execute the body of the state
-	nextState := s.Id // self
-	if s.body != nil {
-		nextState = s.body(s.parent)
+	if nextState, err = s.resolve(nextState); err != nil {
+		return nextState, err
+	}
+	if nextState == StateHistory {
+		if !s.CanTransitionTo(StateHistory) {
+			return nextState, fmt.Errorf("%s -> history: %w", s.describe(), ErrIllegalTransition)
+		}
+		if nextState = s.parent.GetPrevious(); nextState == StateNone {
+			return nextState, fmt.Errorf("%s: %w", s.describe(), ErrNoHistory)
+		}
+	} else if !s.CanTransitionTo(nextState) {
+		return nextState, fmt.Errorf("%s -> %d: %w", s.describe(), nextState, ErrIllegalTransition)
 	}
 
 	// OnExit is only executed if the FSM is transitioning
 	// out of this state to another state. Never executed
 	// for transitions to self
-	if s.Id != nextState && s.onExit != nil {
-		s.onExit(s.parent)
+	if s.Id != nextState {
+		s.exit()
 	}
 
-	return nextState
+	return nextState, nil
+}
+
+// execute the body of the state, if any, and return the requested
+// target: self when there is no body, or StateNone to let the guards
+// decide.
+func (s *State) invoke() (StateId, error) {
+	switch {
+	case s.fallible != nil:
+		next, err := s.fallible(s.parent)
+		if err != nil {
+			return StateNone, s.failed(err)
+		}
+		return next, nil
+	case s.body != nil:
+		return s.body(s.parent), nil
+	case s.hasGuards():
+		return StateNone, nil
+	}
+	return s.Id, nil
+}
+
+// describe the state by id and name for error messages
+func (s *State) describe() string {
+	return fmt.Sprintf("state %d (%s)", s.Id, s.Name)
 }
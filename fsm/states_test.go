@@ -0,0 +1,111 @@
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+const stHelp StateId = 20
+
+// a help state that returns to whichever state asked for it
+func newHelp(r *recorder) *State {
+	return NewState(stHelp, "Help",
+		func(im IStateMachine) { r.record(fmt.Sprint("help from ", im.GetPrevious())) },
+		nil, false,
+		func(IStateMachine) StateId { return StateHistory }).AllowTransitions(StateHistory)
+}
+
+// a state that asks for help on its first visit, then goes on
+func askingHelp(r *recorder, id StateId, name string, next StateId) *State {
+	visits := 0
+	return NewState(id, name,
+		func(IStateMachine) { r.record("enter " + name) }, nil, false,
+		func(IStateMachine) StateId {
+			if visits++; visits == 1 {
+				return stHelp
+			}
+			return next
+		}).AllowTransitions(stHelp, next)
+}
+
+func TestHistoryReturnsToCaller(t *testing.T) {
+	r := &recorder{}
+	sm := NewStateMachine[int]("history",
+		askingHelp(r, stStart, "Start", stMiddle),
+		askingHelp(r, stMiddle, "Middle", stEnd),
+		newHelp(r),
+		NewStateSimple(stEnd, "End", true, nil))
+	if err := sm.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	assertCalls(t, []string{
+		"enter Start", "help from 1", "enter Start",
+		"enter Middle", "help from 2", "enter Middle",
+	}, r.get())
+	if got := sm.GetPrevious(); got != stMiddle {
+		t.Errorf("previous: want %d got %d", stMiddle, got)
+	}
+}
+
+func TestHistoryWithoutPrevious(t *testing.T) {
+	start := NewStateSimple(stStart, "Start", false, func(IStateMachine) StateId {
+		return StateHistory
+	}).AllowTransitions(StateHistory, stEnd)
+	sm := NewStateMachine[int]("no history", start, NewStateSimple(stEnd, "End", true, nil))
+	if err := sm.Start(); !errors.Is(err, ErrNoHistory) {
+		t.Fatalf("want ErrNoHistory got %v", err)
+	}
+}
+
+func TestHistoryNotDeclared(t *testing.T) {
+	start := NewStateSimple(stStart, "Start", false, func(IStateMachine) StateId {
+		return StateHistory
+	}).AllowTransitions(stEnd)
+	sm := NewStateMachine[int]("undeclared history", start, NewStateSimple(stEnd, "End", true, nil))
+	if err := sm.Start(); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("want ErrIllegalTransition got %v", err)
+	}
+}
+
+func TestHistoryValidation(t *testing.T) {
+	// the help state can only return to its caller, which can not end
+	sm := NewStateMachine[int]("dead end",
+		NewStateSimple(stStart, "Start", false, nil).AllowTransitions(stHelp),
+		newHelp(&recorder{}),
+		NewStateSimple(stEnd, "End", true, nil))
+	err := sm.IsValid()
+	if !errors.Is(err, ErrDeadEndState) || !errors.Is(err, ErrUnreachableState) {
+		t.Errorf("want ErrDeadEndState and ErrUnreachableState got %v", err)
+	}
+	if errors.Is(err, ErrMissingTarget) {
+		t.Errorf("StateHistory is reported as a missing target: %v", err)
+	}
+}
+
+func TestPreviousIgnoresSelfTransitions(t *testing.T) {
+	count := 0
+	var previous []StateId
+	loop := NewStateSimple(stMiddle, "Loop", false, func(im IStateMachine) StateId {
+		previous = append(previous, im.GetPrevious())
+		if count++; count < 3 {
+			return stMiddle
+		}
+		return stEnd
+	})
+	sm := NewStateMachine[int]("self",
+		NewStateSimple(stStart, "Start", false, func(IStateMachine) StateId { return stMiddle }),
+		loop, NewStateSimple(stEnd, "End", true, nil))
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range previous {
+		if id != stStart {
+			t.Errorf("previous: want %d got %v", stStart, previous)
+			break
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Step-by-step execution of a state machine, one state at a time, for
+ * debugging or for driving it from an event loop.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// the position of a running machine in between states
+type cursor struct {
+	last    *State // the state executed last, if any
+	current *State // the state to execute next
+	limiter *limiter
+	ctx     context.Context
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// execute exactly one state (OnEnter, body and OnExit as applicable)
+// and return the id of the state to be executed next, which Current()
+// then reports, and whether the machine is done. The first step
+// validates and starts the machine like Start() does. After the
+// terminal state or an error the machine stops, and the next step
+// starts it over. Step must not be mixed with Start() or Run(), and it
+// runs with context.Background() whatever the context of an earlier Run().
+func (sm *StateMachine[T]) Step() (StateId, bool, error) {
+	sm.mu.Lock()
+	c := sm.stepping
+	sm.mu.Unlock()
+
+	if c == nil {
+		if err := sm.IsValid(); err != nil {
+			return StateNone, false, sm.notifyError(fmt.Errorf("state machine %q is invalid: %w", sm.name, err))
+		}
+
+		start := sm.resumeState()
+		sm.mu.Lock()
+		sm.ctx = context.Background()
+		sm.isActive = true
+		sm.isFinished = false
+		sm.since = time.Now()
+		sm.mu.Unlock()
+
+		sm.notifyStart()
+		c = sm.newCursor(start)
+		sm.mu.Lock()
+		sm.stepping = c
+		sm.mu.Unlock()
+	}
+
+	next, done, _, err := sm.advance(c, nil)
+	if done || err != nil {
+		sm.mu.Lock()
+		sm.stepping = nil
+		sm.isActive = false
+		sm.mu.Unlock()
+	}
+	return next, done, err
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// a cursor at the state a run begins with
+func (sm *StateMachine[T]) newCursor(start *State) *cursor {
+	return &cursor{
+		last:    nil,
+		current: start,
+		limiter: sm.newLimiter(),
+		ctx:     sm.Context(),
+	}
+}
@@ -0,0 +1,96 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestStepExecutesOneState(t *testing.T) {
+	r := &recorder{}
+	sm := newLinear(r)
+
+	next, done, err := sm.Step()
+	if err != nil || done || next != stMiddle {
+		t.Fatalf("first step: %d %t %v", next, done, err)
+	}
+	if got := sm.Current(); got != stMiddle {
+		t.Errorf("current: want %d got %d", stMiddle, got)
+	}
+	if !sm.IsActive() {
+		t.Error("not active in between steps")
+	}
+	assertCalls(t, []string{"enter Start", "body Start", "exit Start"}, r.get())
+
+	if next, done, err = sm.Step(); err != nil || done || next != stEnd {
+		t.Fatalf("second step: %d %t %v", next, done, err)
+	}
+	if _, done, err = sm.Step(); err != nil || !done {
+		t.Fatalf("last step: %t %v", done, err)
+	}
+	if !sm.IsDone() || sm.IsActive() {
+		t.Errorf("status: %s", sm)
+	}
+
+	// the next step starts over
+	if next, _, _ = sm.Step(); next != stMiddle {
+		t.Errorf("restart: want %d got %d", stMiddle, next)
+	}
+}
+
+func TestStepInvalidMachine(t *testing.T) {
+	sm := NewStateMachine[int]("invalid", NewStateSimple(stStart, "Start", false, nil))
+	if _, _, err := sm.Step(); err == nil {
+		t.Fatal("an invalid machine was stepped")
+	}
+	if sm.IsActive() {
+		t.Error("an invalid machine is active")
+	}
+}
+
+func TestStepWithConcurrentGetters(t *testing.T) {
+	sm := newLinear(&recorder{})
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_ = sm.Current()
+			_ = sm.IsActive()
+			_ = sm.History()
+		}
+	}()
+
+	for done := false; !done; {
+		var err error
+		if _, done, err = sm.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestStepAfterCancelledRun(t *testing.T) {
+	sm := newLinear(&recorder{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sm.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled got %v", err)
+	}
+
+	next, done, err := sm.Step()
+	if err != nil || done || next != stMiddle {
+		t.Fatalf("step after a cancelled run: %d %t %v", next, done, err)
+	}
+	if err := sm.Context().Err(); err != nil {
+		t.Errorf("stepping with the context of the run: %v", err)
+	}
+}
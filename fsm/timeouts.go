@@ -0,0 +1,132 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Timed states: a state whose body does not finish in time takes a
+ * timeout transition, and after too many consecutive timeouts an
+ * escalation transition (e.g. repeat the menu, then hang up).
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	ErrStateTimeout = errors.New("state timed out")
+)
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	T y p e s
+ *-----------------------------------------------------------------*/
+
+// the time limit of a state body
+type timeout struct {
+	limit      time.Duration // zero means no time limit
+	target     StateId       // taken when the body times out
+	max        int           // consecutive timeouts before escalating
+	escalation StateId       // taken after max consecutive timeouts
+	count      int           // consecutive timeouts so far
+}
+
+// a transition that is not declared but implied, e.g. by a timeout
+type impliedTransition struct {
+	target StateId
+	label  string
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// limit the time the body of the state may take. If it has not
+// returned within the limit, the state transitions to target, for
+// example itself to repeat a menu. The transition is allowed and
+// validated (see StateMachine.IsValid) without being declared, so a
+// state that declares no transitions may still go anywhere. The body
+// runs as usual and its Context() is cancelled with ErrStateTimeout as
+// cause when the time is up: a body that waits, for example for an
+// answer (see ask.InputRequest.WithContext), must watch it and return.
+// What it returns after the limit is ignored. Composite states can not
+// be timed.
+func (s *State) WithTimeout(limit time.Duration, target StateId) *State {
+	s.timeout.limit = limit
+	s.timeout.target = target
+	return s
+}
+
+// after count consecutive timeouts (see WithTimeout) transition to the
+// escalation target instead, for example to hang up. Like the timeout
+// target it need not be declared. The count restarts whenever the body
+// returns in time or the escalation is taken.
+func (s *State) MaxTimeouts(count int, escalation StateId) *State {
+	s.timeout.max = count
+	s.timeout.escalation = escalation
+	return s
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// the timeout and escalation transitions of a timed state
+func (s *State) timeoutTransitions() []impliedTransition {
+	implied := make([]impliedTransition, 0, 2)
+	if s.timeout.limit <= 0 {
+		return implied
+	}
+	implied = append(implied, impliedTransition{s.timeout.target, "timeout"})
+	if s.timeout.max > 0 {
+		implied = append(implied, impliedTransition{s.timeout.escalation, fmt.Sprintf("%d timeouts", s.timeout.max)})
+	}
+	return implied
+}
+
+// whether the target is that of a timeout or escalation transition
+func (s *State) isTimeoutTarget(target StateId) bool {
+	for _, t := range s.timeoutTransitions() {
+		if t.target == target {
+			return true
+		}
+	}
+	return false
+}
+
+// execute the body within the time limit of the state and return the
+// requested target, or the timeout or escalation target. The body runs
+// on the machine's goroutine with the time limit in its Context().
+func (s *State) timed() (StateId, error) {
+	outer := s.parent.Context()
+	ctx, cancel := context.WithTimeoutCause(outer, s.timeout.limit, ErrStateTimeout)
+	defer cancel()
+
+	if machine, ok := s.parent.(lifecycle); ok {
+		defer machine.swapContext(machine.swapContext(ctx))
+	}
+
+	next, err := s.invoke()
+	if ctx.Err() == nil {
+		s.timeout.count = 0
+		return next, err
+	}
+
+	if outer.Err() != nil {
+		return StateNone, fmt.Errorf("%s: %w", s.describe(), context.Cause(outer))
+	}
+	s.timeout.count++
+	if s.timeout.max > 0 && s.timeout.count >= s.timeout.max {
+		s.timeout.count = 0
+		s.parent.SetCause(fmt.Sprintf("%d timeouts", s.timeout.max))
+		return s.timeout.escalation, nil
+	}
+	s.parent.SetCause("timeout")
+	return s.timeout.target, nil
+}
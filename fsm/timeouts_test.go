@@ -0,0 +1,100 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// a state that waits until its context is done, except on the given
+// attempts which return at once
+func newWaiting(attempts *int, prompt ...int) *State {
+	return NewStateSimple(stStart, "Waiting", false, func(im IStateMachine) StateId {
+		*attempts++
+		for _, p := range prompt {
+			if p == *attempts {
+				return stMiddle
+			}
+		}
+		<-im.Context().Done()
+		return stMiddle // ignored when the time is up
+	})
+}
+
+func newTimed(waiting *State) *StateMachine[int] {
+	return NewStateMachine[int]("timed", waiting,
+		NewStateSimple(stMiddle, "Answered", true, nil),
+		NewStateSimple(stEnd, "Hung up", true, nil))
+}
+
+func TestTimeoutEscalates(t *testing.T) {
+	attempts := 0
+	waiting := newWaiting(&attempts).WithTimeout(10*time.Millisecond, stStart).MaxTimeouts(3, stEnd)
+	sm := newTimed(waiting)
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Current() != stEnd || attempts != 3 {
+		t.Errorf("in %d after %d attempts", sm.Current(), attempts)
+	}
+	history := sm.History()
+	if got := history[len(history)-1].Cause; got != "3 timeouts" {
+		t.Errorf("cause: want %q got %q", "3 timeouts", got)
+	}
+}
+
+func TestTimeoutAnsweredInTime(t *testing.T) {
+	attempts := 0
+	waiting := newWaiting(&attempts, 2).WithTimeout(10*time.Millisecond, stStart).MaxTimeouts(3, stEnd)
+	sm := newTimed(waiting)
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Current() != stMiddle || attempts != 2 {
+		t.Errorf("in %d after %d attempts", sm.Current(), attempts)
+	}
+	if got := sm.History()[0].Cause; got != "timeout" {
+		t.Errorf("cause: want %q got %q", "timeout", got)
+	}
+}
+
+func TestTimeoutCancelled(t *testing.T) {
+	attempts := 0
+	exited := false
+	waiting := newWaiting(&attempts).WithTimeout(time.Minute, stStart)
+	waiting.onExit = func(IStateMachine) { exited = true }
+	sm := newTimed(waiting)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := sm.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded got %v", err)
+	}
+	if !exited {
+		t.Error("the timed state was not exited")
+	}
+}
+
+func TestTimeoutTargetMustExist(t *testing.T) {
+	attempts := 0
+	waiting := newWaiting(&attempts).WithTimeout(time.Second, stError)
+	if err := newTimed(waiting).IsValid(); !errors.Is(err, ErrMissingTarget) {
+		t.Fatalf("want ErrMissingTarget got %v", err)
+	}
+}
+
+func TestTimeoutTargetsNeedNoDeclaration(t *testing.T) {
+	waiting := NewStateSimple(stStart, "Waiting", false, func(IStateMachine) StateId {
+		return stMiddle
+	}).WithTimeout(time.Second, stStart).MaxTimeouts(3, stEnd)
+	if err := newTimed(waiting).IsValid(); err != nil {
+		t.Fatal(err)
+	}
+	if got := waiting.Transitions(); len(got) != 0 {
+		t.Errorf("the timeouts declared transitions: %v", got)
+	}
+	if !waiting.CanTransitionTo(stEnd) {
+		t.Error("the escalation target is not allowed")
+	}
+}
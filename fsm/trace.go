@@ -0,0 +1,107 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Transition history of a Finite State Machine and pluggable tracers
+ * to stream it, for example as JSON lines for auditing.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+/* ----------------------------------------------------------------
+ *				I n t e r f a c e s
+ *-----------------------------------------------------------------*/
+
+var _ Tracer = (*JSONTracer)(nil)
+
+// Interface of a receiver of the transitions made by a state machine
+type Tracer interface {
+	// called after every transition
+	Trace(entry TraceEntry)
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// A transition made by a state machine while running
+type TraceEntry struct {
+	Machine  string        `json:"machine"`
+	From     StateId       `json:"from"`
+	FromName string        `json:"fromName"`
+	To       StateId       `json:"to"`
+	ToName   string        `json:"toName,omitempty"`
+	At       time.Time     `json:"at"`
+	Duration time.Duration `json:"duration"` // time spent in From
+	Cause    string        `json:"cause,omitempty"`
+}
+
+// A Tracer that writes every transition as a line of JSON
+type JSONTracer struct {
+	enc *json.Encoder
+	err error
+	mu  sync.Mutex
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) a tracer that streams the transitions as JSON lines to w.
+func NewJSONTracer(w io.Writer) *JSONTracer {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONTracer{
+		enc: enc,
+		err: nil,
+	}
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// implements Tracer. The first write error is retained (see Err) and
+// no further entries are written.
+func (t *JSONTracer) Trace(entry TraceEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.err == nil {
+		t.err = t.enc.Encode(entry)
+	}
+}
+
+// get the first error that occurred while writing, if any
+func (t *JSONTracer) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.err
+}
+
+// get a copy of every transition made since the machine was created
+// (or restored), oldest first.
+func (sm *StateMachine[T]) History() []TraceEntry {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return append([]TraceEntry(nil), sm.history...)
+}
+
+// set the tracer that receives every transition as it happens. Use nil
+// to remove it.
+func (sm *StateMachine[T]) SetTracer(tracer Tracer) *StateMachine[T] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.tracer = tracer
+	return sm
+}
@@ -0,0 +1,36 @@
+package fsm
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONTracer(t *testing.T) {
+	var sb strings.Builder
+	tracer := NewJSONTracer(&sb)
+	sm := newLinear(&recorder{}).SetTracer(tracer)
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracer.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := make([]TraceEntry, 0)
+	scanner := bufio.NewScanner(strings.NewReader(sb.String()))
+	for scanner.Scan() {
+		var entry TraceEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries: want 2 got %d", len(entries))
+	}
+	if e := entries[1]; e.Machine != "linear" || e.From != stMiddle || e.ToName != "End" {
+		t.Errorf("entry: %+v", e)
+	}
+}
@@ -0,0 +1,113 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * States whose handlers have compile-time checked access to the
+ * state data of their StateMachine[T].
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"errors"
+	"fmt"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	ErrStateDataType = errors.New("state belongs to a state machine of another data type")
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// Typed callback function signature for a state's OnEnter event.
+type TypedEnterHandler[T any] func(*StateMachine[T], *T)
+
+// Typed callback function signature for a state's OnExit event.
+type TypedExitHandler[T any] func(*StateMachine[T], *T)
+
+// Typed callback function signature for a state's body.
+type TypedStateHandler[T any] func(*StateMachine[T], *T) StateId
+
+// Typed guard condition that receives the state data as *T
+type TypedGuardFunc[T any] func(*StateMachine[T], *T) bool
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) adapts a typed guard condition to a GuardFunc. The typed
+// guard always receives the state data, so in event-driven mode the
+// payload given to Fire() is not available to it.
+func NewTypedGuard[T any](guard TypedGuardFunc[T]) GuardFunc {
+	return func(im IStateMachine, _ any) bool {
+		sm := im.(*StateMachine[T])
+		return guard(sm, sm.Data())
+	}
+}
+
+// (ctor) Creates a new instance of a State whose handlers receive the
+// StateMachine[T] and its state data as *T, instead of having to cast
+// IStateMachine.GetStateData(). The state must be added to a machine
+// of the same data type T, which is verified by IsValid().
+func NewTypedState[T any](id StateId, name string, onEnter TypedEnterHandler[T], onExit TypedExitHandler[T], terminal bool, body TypedStateHandler[T]) *State {
+	var enter OnEnterHandler = nil
+	if onEnter != nil {
+		enter = func(im IStateMachine) {
+			sm := im.(*StateMachine[T])
+			onEnter(sm, sm.Data())
+		}
+	}
+
+	var exit OnExitHandler = nil
+	if onExit != nil {
+		exit = func(im IStateMachine) {
+			sm := im.(*StateMachine[T])
+			onExit(sm, sm.Data())
+		}
+	}
+
+	var main StateMainHandler = nil
+	if body != nil {
+		main = func(im IStateMachine) StateId {
+			sm := im.(*StateMachine[T])
+			return body(sm, sm.Data())
+		}
+	}
+
+	state := NewState(id, name, enter, exit, terminal, main)
+	state.typeCheck = func(im IStateMachine) error {
+		if _, ok := im.(*StateMachine[T]); !ok {
+			return fmt.Errorf("%w: %s expects %T", ErrStateDataType, state.describe(), (*T)(nil))
+		}
+		return nil
+	}
+	return state
+}
+
+// (ctor) simplified constructor for a typed state which only has a body
+// but no OnEnter nor OnExit callbacks.
+func NewTypedStateSimple[T any](id StateId, name string, terminal bool, body TypedStateHandler[T]) *State {
+	return NewTypedState(id, name, nil, nil, terminal, body)
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// get the typed state data. If none was set with SetUserDataObject()
+// a zero value is allocated so that typed handlers never receive nil.
+func (sm *StateMachine[T]) Data() *T {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.stateData == nil {
+		sm.stateData = new(T)
+	}
+	return sm.stateData
+}
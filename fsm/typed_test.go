@@ -0,0 +1,64 @@
+package fsm
+
+import (
+	"errors"
+	"testing"
+)
+
+type order struct {
+	items int
+	paid  bool
+}
+
+func TestTypedStates(t *testing.T) {
+	cart := NewTypedState(stStart, "Cart",
+		func(_ *StateMachine[order], o *order) { o.items++ },
+		nil,
+		false,
+		func(_ *StateMachine[order], o *order) StateId {
+			if o.paid {
+				return stEnd
+			}
+			return stMiddle
+		})
+	reached := StateNone
+	reach := func(id StateId) TypedStateHandler[order] {
+		return func(*StateMachine[order], *order) StateId { reached = id; return id }
+	}
+	sm := NewStateMachine[order]("typed", cart,
+		NewTypedStateSimple(stMiddle, "Unpaid", true, reach(stMiddle)),
+		NewTypedStateSimple(stEnd, "Paid", true, reach(stEnd)))
+	sm.SetUserDataObject(&order{items: 0, paid: true})
+
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if reached != stEnd || sm.Data().items != 1 {
+		t.Errorf("in %d with %d items", reached, sm.Data().items)
+	}
+}
+
+func TestTypedStateOfAnotherType(t *testing.T) {
+	sm := NewStateMachine[int]("mistyped",
+		NewTypedStateSimple(stStart, "Cart", false, func(*StateMachine[order], *order) StateId { return stEnd }),
+		NewStateSimple(stEnd, "End", true, nil))
+	if err := sm.IsValid(); !errors.Is(err, ErrStateDataType) {
+		t.Fatalf("want ErrStateDataType got %v", err)
+	}
+}
+
+func TestTypedGuard(t *testing.T) {
+	paid := NewTypedGuard(func(_ *StateMachine[order], o *order) bool { return o.paid })
+	cart := NewStateSimple(stStart, "Cart", false, nil).When(stEnd, "paid", 0, paid).Otherwise(stMiddle)
+	sm := NewStateMachine[order]("typed guard", cart,
+		NewStateSimple(stMiddle, "Unpaid", true, nil),
+		NewStateSimple(stEnd, "Paid", true, nil))
+	sm.SetUserDataObject(&order{items: 1, paid: false})
+
+	if err := sm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Current(); got != stMiddle {
+		t.Errorf("current: want %d got %d", stMiddle, got)
+	}
+}
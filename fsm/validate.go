@@ -0,0 +1,248 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Structural validation of a Finite State Machine's graph.
+ *-----------------------------------------------------------------*/
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	ErrNoStates          = errors.New("no states have been defined")
+	ErrOnlyInitial       = errors.New("only has an initial state")
+	ErrNoInitialState    = errors.New("no initial state")
+	ErrNoTerminalState   = errors.New("there must be at least one terminal state")
+	ErrNilState          = errors.New("nil state")
+	ErrDuplicateState    = errors.New("duplicate state id")
+	ErrMissingTarget     = errors.New("transition targets a missing state")
+	ErrUnreachableState  = errors.New("unreachable state")
+	ErrDeadEndState      = errors.New("state cannot reach any terminal state")
+	ErrUnknownState      = errors.New("unknown state")
+	ErrIllegalTransition = errors.New("transition not declared")
+	ErrNoHistory         = errors.New("no previous state to return to")
+)
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// performs all the structural checks on the state machine and returns
+// every problem found (joined) or nil if the graph is sound. States
+// that do not declare their transitions (see State.AllowTransitions)
+// are assumed to be able to transition to any state. For a nested
+// machine, outer tells which states of the outer machines its states
+// may exit to; such a machine needs no terminal state of its own.
+func (sm *StateMachine[T]) validate(outer func(StateId) bool) error {
+	if len(sm.states) == 0 {
+		return ErrNoStates
+	}
+	if sm.initialState == nil {
+		return ErrNoInitialState
+	}
+	if len(sm.states) == 1 && outer == nil {
+		return ErrOnlyInitial
+	}
+
+	problems := make([]error, 0)
+	if sm.nilStates > 0 {
+		problems = append(problems, fmt.Errorf("%w: %d given", ErrNilState, sm.nilStates))
+	}
+	for _, id := range sm.duplicates {
+		problems = append(problems, fmt.Errorf("%w: %d", ErrDuplicateState, id))
+	}
+
+	ids := sm.sortedIds()
+	hasTerminal := false
+	for _, id := range ids {
+		state := sm.states[id]
+		if state.isTerminal {
+			hasTerminal = true
+		}
+		if state.typeCheck != nil {
+			if err := state.typeCheck(sm); err != nil {
+				problems = append(problems, err)
+			}
+		}
+		if state.nested != nil {
+			if err := state.nested.validateNested(sm.knowsWithin(outer)); err != nil {
+				problems = append(problems, fmt.Errorf("%s: %w", state.describe(), err))
+			}
+		}
+		for _, region := range state.regions {
+			// regions must finish on their own
+			if err := region.validateNested(nil); err != nil {
+				problems = append(problems, fmt.Errorf("%s: region %q: %w", state.describe(), region.GetName(), err))
+			}
+		}
+		for _, target := range state.transitions {
+			if target == StateHistory {
+				continue
+			}
+			if _, ok := sm.states[target]; !ok && (outer == nil || !outer(target)) {
+				problems = append(problems, fmt.Errorf("%w: %s -> %d", ErrMissingTarget, state.describe(), target))
+			}
+		}
+		for _, t := range state.timeoutTransitions() {
+			if _, ok := sm.states[t.target]; !ok && (outer == nil || !outer(t.target)) {
+				problems = append(problems, fmt.Errorf("%w: %s -> %d (%s)", ErrMissingTarget, state.describe(), t.target, t.label))
+			}
+		}
+	}
+	if _, ok := sm.states[sm.errorState]; !ok && sm.errorState != StateNone {
+		problems = append(problems, fmt.Errorf("%w: error state %d", ErrMissingTarget, sm.errorState))
+	}
+	for _, t := range sm.events {
+		if _, ok := sm.states[t.From]; !ok {
+			problems = append(problems, fmt.Errorf("%w: event %q from unknown state %d", ErrMissingTarget, t.Event, t.From))
+		}
+		if _, ok := sm.states[t.To]; !ok {
+			problems = append(problems, fmt.Errorf("%w: event %q from state %d -> %d", ErrMissingTarget, t.Event, t.From, t.To))
+		}
+	}
+	if !hasTerminal && outer == nil {
+		problems = append(problems, ErrNoTerminalState)
+		return errors.Join(problems...)
+	}
+
+	// every state must be reachable from the initial state...
+	reachable := sm.reachableFrom(sm.initialState.Id)
+	for _, id := range ids {
+		if !reachable[id] {
+			problems = append(problems, fmt.Errorf("%w: %s", ErrUnreachableState, sm.states[id].describe()))
+		}
+	}
+
+	// ...and every reachable state must be able to reach a terminal
+	for _, id := range ids {
+		if !reachable[id] {
+			continue
+		}
+		if !sm.reachesTerminal(id, outer) {
+			problems = append(problems, fmt.Errorf("%w: %s", ErrDeadEndState, sm.states[id].describe()))
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// the ids of all the states in ascending order so that the problems
+// are reported in a deterministic order.
+func (sm *StateMachine[T]) sortedIds() []StateId {
+	ids := make([]StateId, 0, len(sm.states))
+	for id := range sm.states {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// the states a given state may transition to: those it declared plus
+// the targets of its event-driven transitions, of its timeouts (see
+// State.WithTimeout) and the error state (see SetErrorState). A state
+// that declares none may transition anywhere, and one that declares
+// StateHistory may return to any of the states it can be entered from.
+// Terminal states have no successors because the machine stops after
+// running them.
+func (sm *StateMachine[T]) successors(id StateId) []StateId {
+	targets := sm.directSuccessors(id)
+	if slices.Contains(targets, StateHistory) {
+		targets = append(targets, sm.predecessors(id)...)
+	}
+	return targets
+}
+
+// the successors of a state without resolving StateHistory
+func (sm *StateMachine[T]) directSuccessors(id StateId) []StateId {
+	state := sm.states[id]
+	if state.isTerminal {
+		return nil
+	}
+	targets := slices.Clone(state.transitions)
+	for _, t := range sm.events {
+		if t.From == id {
+			targets = append(targets, t.To)
+		}
+	}
+	if len(targets) == 0 {
+		return sm.sortedIds()
+	}
+	for _, t := range state.timeoutTransitions() {
+		targets = append(targets, t.target)
+	}
+	if sm.errorState != StateNone && sm.errorState != id {
+		targets = append(targets, sm.errorState)
+	}
+	return targets
+}
+
+// the states that may transition to a given state
+func (sm *StateMachine[T]) predecessors(id StateId) []StateId {
+	sources := make([]StateId, 0)
+	for _, other := range sm.sortedIds() {
+		if other != id && slices.Contains(sm.directSuccessors(other), id) {
+			sources = append(sources, other)
+		}
+	}
+	return sources
+}
+
+// the set of states that can be reached from a state (inclusive)
+func (sm *StateMachine[T]) reachableFrom(id StateId) map[StateId]bool {
+	visited := map[StateId]bool{id: true}
+	pending := []StateId{id}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for _, next := range sm.successors(current) {
+			if _, ok := sm.states[next]; ok && !visited[next] {
+				visited[next] = true
+				pending = append(pending, next)
+			}
+		}
+	}
+	return visited
+}
+
+// whether any terminal state can be reached from a state or, in a
+// nested machine, any state of the outer machines.
+func (sm *StateMachine[T]) reachesTerminal(id StateId, outer func(StateId) bool) bool {
+	for reached := range sm.reachableFrom(id) {
+		if sm.states[reached].isTerminal {
+			return true
+		}
+		if outer == nil {
+			continue
+		}
+		targets := sm.successors(reached)
+		if len(sm.states[reached].transitions) == 0 {
+			return true // undeclared, it may exit anywhere
+		}
+		for _, target := range targets {
+			if _, own := sm.states[target]; !own && outer(target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// a function that tells whether a state is known by this machine or
+// by any of the outer machines.
+func (sm *StateMachine[T]) knowsWithin(outer func(StateId) bool) func(StateId) bool {
+	return func(id StateId) bool {
+		if _, ok := sm.states[id]; ok {
+			return true
+		}
+		return outer != nil && outer(id)
+	}
+}
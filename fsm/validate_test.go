@@ -0,0 +1,82 @@
+package fsm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		sm   *StateMachine[int]
+		want error
+	}{
+		"no states": {
+			sm:   NewStateMachine[int]("empty", nil),
+			want: ErrNoStates,
+		},
+		"no terminal": {
+			sm: NewStateMachine[int]("endless",
+				NewStateSimple(stStart, "Start", false, nil),
+				NewStateSimple(stMiddle, "Middle", false, nil)),
+			want: ErrNoTerminalState,
+		},
+		"duplicate": {
+			sm: NewStateMachine[int]("twice",
+				NewStateSimple(stStart, "Start", false, nil),
+				NewStateSimple(stEnd, "End", true, nil),
+				NewStateSimple(stEnd, "End again", true, nil)),
+			want: ErrDuplicateState,
+		},
+		"missing target": {
+			sm: NewStateMachine[int]("missing",
+				NewStateSimple(stStart, "Start", false, nil).AllowTransitions(stEnd, stError),
+				NewStateSimple(stEnd, "End", true, nil)),
+			want: ErrMissingTarget,
+		},
+		"unreachable": {
+			sm: NewStateMachine[int]("unreachable",
+				NewStateSimple(stStart, "Start", false, nil).AllowTransitions(stEnd),
+				NewStateSimple(stMiddle, "Middle", false, nil).AllowTransitions(stEnd),
+				NewStateSimple(stEnd, "End", true, nil)),
+			want: ErrUnreachableState,
+		},
+		"dead end": {
+			sm: NewStateMachine[int]("dead end",
+				NewStateSimple(stStart, "Start", false, nil).AllowTransitions(stMiddle, stEnd),
+				NewStateSimple(stMiddle, "Middle", false, nil).AllowTransitions(stMiddle),
+				NewStateSimple(stEnd, "End", true, nil)),
+			want: ErrDeadEndState,
+		},
+	} {
+		if err := tc.sm.IsValid(); !errors.Is(err, tc.want) {
+			t.Errorf("%s: want %v got %v", name, tc.want, err)
+		}
+		if err := tc.sm.Start(); !errors.Is(err, tc.want) {
+			t.Errorf("%s: started with %v", name, err)
+		}
+	}
+}
+
+func TestIllegalTransition(t *testing.T) {
+	sm := NewStateMachine[int]("illegal",
+		NewStateSimple(stStart, "Start", false, func(IStateMachine) StateId { return stMiddle }).AllowTransitions(stMiddle),
+		NewStateSimple(stMiddle, "Middle", false, func(IStateMachine) StateId { return stStart }).AllowTransitions(stEnd),
+		NewStateSimple(stEnd, "End", true, nil))
+	if err := sm.Start(); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("want ErrIllegalTransition got %v", err)
+	}
+}
+
+func TestDeclaredTransitions(t *testing.T) {
+	open := NewStateSimple(stStart, "Open", false, nil)
+	if !open.CanTransitionTo(stError) {
+		t.Error("a state that declares no transitions may not go anywhere")
+	}
+	closed := NewStateSimple(stStart, "Closed", false, nil).AllowTransitions(stMiddle, stEnd)
+	if got := closed.Transitions(); len(got) != 2 || got[0] != stMiddle || got[1] != stEnd {
+		t.Errorf("transitions: want [%d %d] got %v", stMiddle, stEnd, got)
+	}
+	if closed.CanTransitionTo(stError) {
+		t.Error("an undeclared transition is allowed")
+	}
+}
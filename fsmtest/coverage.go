@@ -0,0 +1,179 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Coverage of the states and transitions of a state machine that
+ * were exercised by scripted runs.
+ *-----------------------------------------------------------------*/
+package fsmtest
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lordofscripts/goask/fsm"
+)
+
+/* ----------------------------------------------------------------
+ *				I n t e r f a c e s
+ *-----------------------------------------------------------------*/
+
+// what coverage needs of a StateMachine[T] of any data type T
+type machine interface {
+	States() []*fsm.State
+	EventTransitions() []fsm.Transition
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// A transition between two states
+type Edge struct {
+	From fsm.StateId
+	To   fsm.StateId
+}
+
+// The states and transitions exercised by the runs of a Runner. The
+// transitions are those declared (see fsm.State.AllowTransitions) and
+// event-driven ones, plus any other transition that was taken.
+type Coverage struct {
+	names    map[fsm.StateId]string
+	visited  map[fsm.StateId]int
+	declared map[Edge]bool
+	taken    map[Edge]int
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+func newCoverage() *Coverage {
+	return &Coverage{
+		names:    make(map[fsm.StateId]string),
+		visited:  make(map[fsm.StateId]int),
+		declared: make(map[Edge]bool),
+		taken:    make(map[Edge]int),
+	}
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// get the number of states visited and the number of states
+func (c *Coverage) States() (int, int) {
+	return len(c.visited), len(c.names)
+}
+
+// get the number of transitions taken and the number of transitions
+func (c *Coverage) Transitions() (int, int) {
+	return len(c.taken), len(c.edges())
+}
+
+// get the states that were never visited
+func (c *Coverage) UncoveredStates() []fsm.StateId {
+	missing := make([]fsm.StateId, 0)
+	for id := range c.names {
+		if c.visited[id] == 0 {
+			missing = append(missing, id)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// get the transitions that were never taken
+func (c *Coverage) UncoveredTransitions() []Edge {
+	missing := make([]Edge, 0)
+	for _, e := range c.edges() {
+		if c.taken[e] == 0 {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+// implements fmt.Stringer with a report of the coverage
+func (c *Coverage) String() string {
+	var sb strings.Builder
+	visited, states := c.States()
+	taken, transitions := c.Transitions()
+	fmt.Fprintf(&sb, "states: %d/%d (%s)\n", visited, states, percent(visited, states))
+	for _, id := range c.UncoveredStates() {
+		fmt.Fprintf(&sb, "\tnot visited: %s\n", c.name(id))
+	}
+	fmt.Fprintf(&sb, "transitions: %d/%d (%s)\n", taken, transitions, percent(taken, transitions))
+	for _, e := range c.UncoveredTransitions() {
+		fmt.Fprintf(&sb, "\tnot taken: %s -> %s\n", c.name(e.From), c.name(e.To))
+	}
+	return sb.String()
+}
+
+/* ----------------------------------------------------------------
+ *				P r i v a t e	M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// add the states and declared transitions of a machine
+func (c *Coverage) declare(sm machine) {
+	for _, state := range sm.States() {
+		c.names[state.Id] = state.Name
+		for _, target := range state.Transitions() {
+			if target != fsm.StateHistory {
+				c.declared[Edge{state.Id, target}] = true
+			}
+		}
+	}
+	for _, t := range sm.EventTransitions() {
+		c.declared[Edge{t.From, t.To}] = true
+	}
+}
+
+// add the states visited and the transitions taken by a run
+func (c *Coverage) record(path []fsm.StateId) {
+	for i, id := range path {
+		c.visited[id]++
+		if i > 0 {
+			c.taken[Edge{path[i-1], id}]++
+		}
+	}
+}
+
+// all the transitions, declared or taken, in order
+func (c *Coverage) edges() []Edge {
+	all := make([]Edge, 0, len(c.declared)+len(c.taken))
+	for e := range c.declared {
+		all = append(all, e)
+	}
+	for e := range c.taken {
+		if !c.declared[e] {
+			all = append(all, e)
+		}
+	}
+	slices.SortFunc(all, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+	return all
+}
+
+// the name of a state, else its id
+func (c *Coverage) name(id fsm.StateId) string {
+	if name, ok := c.names[id]; ok && len(name) != 0 {
+		return name
+	}
+	return fmt.Sprintf("%d", id)
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+func percent(part, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", part*100/total)
+}
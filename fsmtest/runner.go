@@ -0,0 +1,179 @@
+/* -----------------------------------------------------------------
+ *					L o r d  O f   S c r i p t s (tm)
+ *				  Copyright (C)2025 Dídimo Grimaldo T.
+ *							   goAsk
+ * - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+ * Test harness for state machines whose states ask questions: runs
+ * them with scripted answers, asserts the path taken and the final
+ * state data, and reports which states and transitions were covered.
+ *-----------------------------------------------------------------*/
+package fsmtest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/fsm"
+)
+
+/* ----------------------------------------------------------------
+ *						G l o b a l s
+ *-----------------------------------------------------------------*/
+
+var (
+	// the limits a scripted run is given unless set with WithLimits
+	DefaultLimits = fsm.Limits{MaxSteps: 1000}
+)
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		T y p e s
+ *-----------------------------------------------------------------*/
+
+// Runs state machines built by a factory with scripted answers and
+// keeps the coverage of all the runs.
+type Runner[T any] struct {
+	build    func() *fsm.StateMachine[T]
+	limits   fsm.Limits
+	coverage *Coverage
+}
+
+// The outcome of a scripted run
+type Session[T any] struct {
+	Path    []fsm.StateId    // states visited, in order
+	Data    *T               // the state data when the machine stopped
+	Output  string           // everything the questions rendered
+	Err     error            // the error returned by Start()
+	History []fsm.TraceEntry // the transitions made
+}
+
+/* ----------------------------------------------------------------
+ *				C o n s t r u c t o r s
+ *-----------------------------------------------------------------*/
+
+// (ctor) a runner of the machines built by the factory, which is called
+// for every run so that each one starts afresh. In cmd/demo-ivr that
+// would be defineStates.
+func NewRunner[T any](build func() *fsm.StateMachine[T]) *Runner[T] {
+	return &Runner[T]{
+		build:    build,
+		limits:   DefaultLimits,
+		coverage: newCoverage(),
+	}
+}
+
+/* ----------------------------------------------------------------
+ *				P u b l i c		M e t h o d s
+ *-----------------------------------------------------------------*/
+
+// set the limits of every run (see fsm.Limits) so that a script that
+// runs out of answers can not make the machine loop forever: at the end
+// of the script every question gets its default answer.
+func (r *Runner[T]) WithLimits(limits fsm.Limits) *Runner[T] {
+	r.limits = limits
+	return r
+}
+
+// run a new machine feeding the answers, one per line, to the questions
+// it asks (see ask.CaptureConsole), and capture what they render.
+func (r *Runner[T]) Run(answers ...string) *Session[T] {
+	sm := r.build()
+	sm.SetLimits(r.limits)
+	r.coverage.declare(sm)
+
+	script := strings.Join(answers, "\n")
+	if len(answers) != 0 {
+		script += "\n"
+	}
+	var output bytes.Buffer
+
+	err := func() error {
+		restore := ask.CaptureConsole(strings.NewReader(script), &output)
+		defer restore()
+		return sm.Start()
+	}()
+
+	history := sm.History()
+	path := pathOf(sm, history)
+	r.coverage.record(path)
+	return &Session[T]{
+		Path:    path,
+		Data:    sm.Data(),
+		Output:  output.String(),
+		Err:     err,
+		History: history,
+	}
+}
+
+// get the coverage of all the runs so far
+func (r *Runner[T]) Coverage() *Coverage {
+	return r.coverage
+}
+
+// assert that the machine stopped without error
+func (s *Session[T]) AssertNoError(t testing.TB) {
+	t.Helper()
+	if s.Err != nil {
+		t.Errorf("unexpected error: %v\npath: %s", s.Err, formatPath(s.History, s.Path))
+	}
+}
+
+// assert the states visited, in order
+func (s *Session[T]) AssertPath(t testing.TB, want ...fsm.StateId) {
+	t.Helper()
+	if !reflect.DeepEqual(s.Path, want) {
+		t.Errorf("unexpected path\n got: %v\nwant: %v\n(%s)", s.Path, want, formatPath(s.History, s.Path))
+	}
+}
+
+// assert the state data when the machine stopped
+func (s *Session[T]) AssertData(t testing.TB, want T) {
+	t.Helper()
+	if !reflect.DeepEqual(*s.Data, want) {
+		t.Errorf("unexpected state data\n got: %+v\nwant: %+v", *s.Data, want)
+	}
+}
+
+// assert that the rendered output contains a text
+func (s *Session[T]) AssertOutput(t testing.TB, text string) {
+	t.Helper()
+	if !strings.Contains(s.Output, text) {
+		t.Errorf("output does not contain %q:\n%s", text, s.Output)
+	}
+}
+
+/* ----------------------------------------------------------------
+ *					F u n c t i o n s
+ *-----------------------------------------------------------------*/
+
+// the states visited: the initial state and the target of every
+// transition made
+func pathOf[T any](sm *fsm.StateMachine[T], history []fsm.TraceEntry) []fsm.StateId {
+	path := make([]fsm.StateId, 0, len(history)+1)
+	if len(history) == 0 {
+		if initial := sm.GetInitial(); initial != nil {
+			path = append(path, initial.Id)
+		}
+		return path
+	}
+	path = append(path, history[0].From)
+	for _, entry := range history {
+		path = append(path, entry.To)
+	}
+	return path
+}
+
+// the path by state names, for messages
+func formatPath(history []fsm.TraceEntry, path []fsm.StateId) string {
+	if len(history) == 0 {
+		return fmt.Sprint(path)
+	}
+	names := []string{history[0].FromName}
+	for _, entry := range history {
+		names = append(names, entry.ToName)
+	}
+	return strings.Join(names, " -> ")
+}
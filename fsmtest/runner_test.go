@@ -0,0 +1,87 @@
+package fsmtest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lordofscripts/goask/ask"
+	"github.com/lordofscripts/goask/fsm"
+)
+
+const (
+	askState fsm.StateId = iota + 1
+	buyState
+	doneState
+)
+
+type order struct {
+	Count int
+}
+
+func newOrderMachine() *fsm.StateMachine[order] {
+	how := fsm.NewTypedStateSimple(askState, "Ask", false, func(_ *fsm.StateMachine[order], data *order) fsm.StateId {
+		data.Count = ask.NewIntInputRequest("How many?", 0).Read()
+		if data.Count > 0 {
+			return buyState
+		}
+		return doneState
+	}).AllowTransitions(buyState, doneState)
+	buy := fsm.NewTypedStateSimple(buyState, "Buy", true, func(_ *fsm.StateMachine[order], data *order) fsm.StateId {
+		ask.NewStringInputRequest("Name", "nobody").Read()
+		return buyState
+	})
+	return fsm.NewStateMachine[order]("order", how, buy, fsm.NewStateSimple(doneState, "Done", true, nil))
+}
+
+func TestRunnerSession(t *testing.T) {
+	runner := NewRunner(newOrderMachine)
+	session := runner.Run("2", "Ann")
+	session.AssertNoError(t)
+	session.AssertPath(t, askState, buyState)
+	session.AssertData(t, order{Count: 2})
+	session.AssertOutput(t, "👉 Ann")
+}
+
+func TestRunnerEndOfScript(t *testing.T) {
+	// the questions left unanswered take their defaults
+	session := NewRunner(newOrderMachine).Run()
+	session.AssertNoError(t)
+	session.AssertPath(t, askState, doneState)
+	session.AssertOutput(t, "How many? [0]: ")
+}
+
+func TestRunnerLimits(t *testing.T) {
+	loop := func() *fsm.StateMachine[order] {
+		spin := fsm.NewStateSimple(askState, "Spin", false, func(fsm.IStateMachine) fsm.StateId { return askState })
+		return fsm.NewStateMachine[order]("loop", spin, fsm.NewStateSimple(doneState, "Done", true, nil))
+	}
+	session := NewRunner(loop).WithLimits(fsm.Limits{MaxSteps: 5}).Run()
+	if session.Err == nil {
+		t.Fatal("the endless loop was not stopped")
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	runner := NewRunner(newOrderMachine)
+	runner.Run("1")
+
+	coverage := runner.Coverage()
+	if visited, states := coverage.States(); visited != 2 || states != 3 {
+		t.Errorf("states: want 2/3 got %d/%d", visited, states)
+	}
+	if got := coverage.UncoveredStates(); !reflect.DeepEqual(got, []fsm.StateId{doneState}) {
+		t.Errorf("uncovered states: %v", got)
+	}
+	if got := coverage.UncoveredTransitions(); !reflect.DeepEqual(got, []Edge{{askState, doneState}}) {
+		t.Errorf("uncovered transitions: %v", got)
+	}
+	if report := coverage.String(); !strings.Contains(report, "not taken: Ask -> Done") {
+		t.Errorf("report:\n%s", report)
+	}
+
+	runner.Run("0")
+	if taken, transitions := coverage.Transitions(); taken != transitions {
+		t.Errorf("transitions: want all got %d/%d\n%s", taken, transitions, coverage)
+	}
+}
@@ -7,7 +7,10 @@
  *-----------------------------------------------------------------*/
 package tty
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /* ----------------------------------------------------------------
  *						G l o b a l s
@@ -215,32 +218,50 @@ func BrightWhite(args ...any) {
 // Show a progress bar of style at row of the console showing a title and
 // an animation followed by the title. It is padded with "OK" when done.
 func ShowProgressAt(style ProgressStyle, row int, title string, percent uint) {
-	var pind []rune
-	if style != ProgressStyle1 && style != ProgressStyle2 && style != ProgressStyle3 {
-		style = ProgressStyle1
+	if percent > 100 {
+		println("percent > 100%")
+		return
 	}
 
+	fmt.Print(ansi_HIDE_CURSOR)
+	Cursor(row, 0)
+	fmt.Println(progressLine(style, title, percent))
+	fmt.Print(ansi_SHOW_CURSOR)
+}
+
+// Render a progress bar like ShowProgressAt does but to a writer, and
+// put the cursor back where it was so that whatever is rendered next
+// is not drawn over what is already on the screen.
+func RenderProgressAt(w io.Writer, style ProgressStyle, row int, title string, percent uint) {
+	if row <= 0 {
+		row = 1
+	}
+	percent = min(percent, 100)
+
+	fmt.Fprint(w, "\033[s", ansi_HIDE_CURSOR)
+	fmt.Fprintf(w, "\033[%d;1H", row)
+	fmt.Fprint(w, progressLine(style, title, percent), "\033[K")
+	fmt.Fprint(w, ansi_SHOW_CURSOR, "\033[u")
+}
+
+// the text of a progress bar: the indicator, the percentage, whether it
+// is done and the title.
+func progressLine(style ProgressStyle, title string, percent uint) string {
+	var pind []rune
 	switch style {
-	case ProgressStyle1:
-		pind = progressIndicators1
 	case ProgressStyle2:
 		pind = progressIndicators2
 	case ProgressStyle3:
 		pind = progressIndicators3
+	default:
+		pind = progressIndicators1
 	}
 
 	var done string = ""
-	if percent > 100 {
-		println("percent > 100%")
-		percent = 100
-		return
-	} else if percent == 100 {
+	if percent == 100 {
 		done = "✓ OK"
 	}
 
 	indicator := pind[int(percent)%len(pind)]
-	fmt.Print(ansi_HIDE_CURSOR)
-	Cursor(row, 0)
-	fmt.Printf("%c %02d%%/100%% %4s %s\n", indicator, percent, done, title)
-	fmt.Print(ansi_SHOW_CURSOR)
+	return fmt.Sprintf("%c %02d%%/100%% %4s %s", indicator, percent, done, title)
 }